@@ -41,6 +41,52 @@
 *					: Refactored producer following :
 *					: https://medium.com/@ninucium/is-using-kafka-with-schema-registry-and-protobuf-worth-it-part-1-1c4a9995a5d3
 *
+*					: 18 Oct 2026
+*					: All random picks (stores, clerks, products, quantities, terminals, sleeps, invoice and FinTransactionID)
+*					: now come from a single seeded source, RandomSeed in *_app.json or -seed on the command line.
+*					: Kafka, Mongo and json_save writers moved into internal/sink behind a Sink interface + registry,
+*					: the Sinks list in *_app.json selects any number of them.
+*					: SIGINT/SIGTERM now stops generation and flushes/closes every sink within ShutdownTimeout seconds
+*					: before printing the end of run summary.
+*					: TargetTps / LoadProfile in *_app.json pace the run at a known rate (internal/pacer) instead of the random Sleep.
+*					: Baskets/payments are now built by a pool of Workers, put back in generation order and fanned out to a
+*					: writer goroutine per sink. Each record has its own random source seeded from the run's, so a seed still
+*					: replays the same stream whatever the worker count.
+*					: Prometheus metrics (client_golang) for the generator and every sink, served on /metrics (MetricsPort)
+*					: and/or pushed to the Pushgateway (PushgatewayURL).
+*					: Payment delay is now Max_payment_delay, and with DelayPayments the payment is held back (internal/scheduler)
+*					: until its PayTimestamp comes around, optionally TimeCompression times faster, so it arrives out of band.
+*					: Backfill mode, BackfillStart/BackfillEnd, generates history on a simulated clock (internal/clock) as fast as
+*					: the sinks allow, event times are now handed out with the jobs instead of each basket taking time.Now().
+*					: TrafficShape, hour of day / day of week multipliers (internal/traffic), shapes the arrival rate, live and
+*					: backfilling, per store curves also weigh which store a basket is sold at.
+*					: Stores in the seed file can have a timezone, opening hours and closed dates (internal/hours), baskets are
+*					: only sold at stores open at the event time, which is stamped in the store's own timezone.
+*					: Products are picked by popularity (weight), per store catalogs (internal/catalog) can limit the products a
+*					: store sells and override their weights and prices.
+*					: Affinity rules in the seed file (internal/affinity) add co-purchased products to the basket, ie: pasta => sauce.
+*					: Basket size, quantity and payment delay are drawn from configurable Distributions (internal/distribution).
+*					: Payments carry their tender (internal/tender), cash, card, eft, voucher or loyalty, weighted per store, and
+*					: split tender baskets are paid with several payments adding up to the total.
+*					: Payments have a status, attempts can be declined or fail and are retried, the last tender can be a partial
+*					: payment and some baskets never get paid, leaving unmatched invoices for reconciliation testing.
+*					: Refunds (internal/refund), some of a paid basket's items brought back up to Max_refund_delay after the
+*					: sale, are a third stream, posted to RefundTopicname / Refundcollection / a _refund.json file.
+*					: Promotions in the seed file (internal/promo), percentage off, buy x get y, by product or category, per
+*					: store and campaign dates, discount the basket items, Nett is after the basket's Discount.
+*					: Loyalty customers (internal/customer), from the seed file and/or generated, with a home store, visit
+*					: frequency and spend segment, are attached to Loyalty_share of the baskets and come back over the run.
+*					: Stores can have their own country, currency and tax rules (internal/tax), several rates, zero rated
+*					: categories, tax inclusive prices, items are taxed per line and the basket's vat broken down per rate.
+*					: Money is computed in integer cents (internal/money), Total is exactly Nett + Vat and the payments exactly
+*					: the Total, the documents carry the cents (*Minor) next to the doubles, Mongo stores amounts as Decimal128.
+*					: Clerks in the seed file can have a home store and shifts (internal/roster), a sale or refund is then
+*					: served by one of the store's own clerks on shift at the time.
+*					: Stores can list their terminals, and Invoice_format (internal/receipt) numbers the receipts per store
+*					: terminal without gaps, ie: {store}-{terminal}-{run}-{date}-{seq}, instead of a random UUID.
+*					: "<env> seed validate" (internal/seedcheck) checks the seed file, ids, names, prices, required sections,
+*					: references between sections and the Store index, prints a report and exits 1 if it isn't valid.
+*
 *	Git				: https://github.com/georgelza/MongoCreator-GoProducer
 *
 *	By				: George Leonard (georgelza@gmail.com) aka georgelza on Discord and Mongo Community Forum
@@ -52,46 +98,113 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"math"
 	"math/rand"
+	"net/http"
 	"os"
+	"os/signal"
 	"runtime"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/TylerBrock/colorjson"
 	"github.com/brianvoe/gofakeit"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
 	"github.com/tkanos/gonfig"
 
 	// My Types/Structs/functions
-	"cmd/internal/kafka"
+	"cmd/internal/affinity"
+	"cmd/internal/catalog"
+	"cmd/internal/clock"
+	"cmd/internal/customer"
+	"cmd/internal/distribution"
+	"cmd/internal/hours"
+	"cmd/internal/money"
+	"cmd/internal/pacer"
+	"cmd/internal/promo"
+	"cmd/internal/receipt"
+	"cmd/internal/refund"
+	"cmd/internal/roster"
+	"cmd/internal/scheduler"
+	"cmd/internal/seedcheck"
+	"cmd/internal/sink"
+	"cmd/internal/tax"
+	"cmd/internal/tender"
+	"cmd/internal/traffic"
 	"cmd/types"
 
-	cpkafka "github.com/confluentinc/confluent-kafka-go/kafka"
 	glog "google.golang.org/grpc/grpclog"
+)
 
-	// Filter JSON array
-	// MongoDB
-	"go.mongodb.org/mongo-driver/bson/bsonrw"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
+var (
+	grpcLog       glog.LoggerV2
+	varSeed       types.TPSeed
+	vGeneral      types.Tp_general
+	pathSep       = string(os.PathSeparator)
+	vRandom       *rand.Rand    // single random source for the run, see initRandom()
+	vSeedOverride *int64        // -seed command line value, overrides RandomSeed from *_app.json
+	vPaymentDelay time.Duration // Max_payment_delay, parsed
+	vRefundDelay  time.Duration // Max_refund_delay, parsed
+	vClock        clock.Clock   // hands out the event times, the wall clock or, when backfilling, a simulated one
+	vBackfill     bool
+	vShape        *traffic.Shape            // TrafficShape, nil when flat
+	vStores       []*hours.Store            // trading hours of varSeed.Stores, same order
+	vRoster       *roster.Roster            // which clerks work where and when, nil when clerks aren't bound to stores
+	vReceipts     *receipt.Numbers          // Invoice_format numbering, nil => random UUIDs, only used by sequence()
+	vCatalogs     []*catalog.Catalog        // what each of varSeed.Stores sells, same order
+	vAffinities   []affinity.Rule           // products bought together
+	vPromotions   []promo.Promotion         // discounts on offer
+	vCustomers    *customer.Population      // loyalty customers, nil when there are none
+	vBasketSize   distribution.Distribution // items per basket, see Distributions
+	vQuantity     distribution.Distribution // quantity per item
+	vPayDelay     distribution.Distribution // seconds from sale to payment
+	vTenders      []*tender.Mix             // how each of varSeed.Stores gets paid, same order
+	vTaxes        []*tax.Rules              // currency and tax rules of varSeed.Stores, same order
 )
 
+// Prometheus metrics, served on /metrics and/or pushed to the Pushgateway, see startMetrics()
 var (
-	grpcLog  glog.LoggerV2
-	varSeed  types.TPSeed
-	vGeneral types.Tp_general
-	pathSep  = string(os.PathSeparator)
-	runId    string
-	vKafka   types.TKafka
-	vMongodb types.TMongodb
-	producer kafka.SRProducer
+	mRecordsGenerated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "goproducer_records_generated_total",
+		Help: "Basket/payment pairs generated"})
+
+	mSinkWrites = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goproducer_sink_writes_total",
+		Help: "Basket/payment pairs written per sink, once committed / delivered when the sink is transactional / async"}, []string{"sink"})
+
+	mSinkFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goproducer_sink_write_failures_total",
+		Help: "Basket/payment pairs a sink failed to write"}, []string{"sink"})
+
+	mRecordLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "goproducer_record_latency_seconds",
+		Help:    "Time from a record being handed out to it being written by the sink",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14)}, []string{"sink"})
+
+	mBasketValue = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "goproducer_basket_value",
+		Help:    "Basket totals per store",
+		Buckets: prometheus.ExponentialBuckets(25, 2, 10)}, []string{"store"})
+
+	mBasketItems = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "goproducer_basket_items",
+		Help:    "Basket lines per store",
+		Buckets: prometheus.LinearBuckets(1, 1, 20)}, []string{"store"})
+
+	mTargetTps = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "goproducer_target_tps",
+		Help: "Rate the pacer is currently aiming for"})
 )
 
 func init() {
@@ -155,124 +268,79 @@ func loadConfig(params ...string) types.Tp_general {
 
 	}
 
-	if vGeneral.Json_to_file == 1 {
-		vGeneral.Output_path = fmt.Sprintf("%s%s%s", vGeneral.CurrentPath, pathSep, vGeneral.Output_path)
+	if vGeneral.ShutdownTimeout <= 0 {
+		vGeneral.ShutdownTimeout = 30
 	}
 
-	if vGeneral.EchoConfig == 1 {
-		grpcLog.Infoln("*")
-		grpcLog.Infoln("* Config:")
-		grpcLog.Infoln("* Current path:", vGeneral.CurrentPath)
-		grpcLog.Infoln("* Config File :", fileName)
-		grpcLog.Infoln("*")
-
-		printConfig(vGeneral)
+	if vGeneral.Workers <= 0 {
+		vGeneral.Workers = 1
 	}
 
-	return vGeneral
-}
-
-// Load Kafka specific configuration Parameters, this is so that we can gitignore this dev_kafka.json file/seperate
-// from the dev_app.json file
-func loadKafka(params ...string) types.TKafka {
-
-	vKafka := types.TKafka{}
-	env := "dev"
-	if len(params) > 0 {
-		env = params[0]
+	if vGeneral.PushInterval <= 0 {
+		vGeneral.PushInterval = 10
 	}
 
-	path, err := os.Getwd()
-	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Problem retrieving current path: %s", err))
-		os.Exit(1)
-
+	if vGeneral.Max_payment_delay == "" {
+		vGeneral.Max_payment_delay = "5m59s"
 	}
-
-	//	fileName := fmt.Sprintf("%s/%s_app.json", path, env)
-	fileName := fmt.Sprintf("%s/%s_kafka.json", path, env)
-	err = gonfig.GetConf(fileName, &vKafka)
-	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Error Reading Kafka File: %s", err))
-		os.Exit(1)
+	vPaymentDelay, err = time.ParseDuration(vGeneral.Max_payment_delay)
+	if err != nil || vPaymentDelay < 0 {
+		grpcLog.Fatalln("Invalid Max_payment_delay: ", vGeneral.Max_payment_delay)
 
 	}
 
-	vGeneral.KafkaConfigFile = fileName
-
-	if vGeneral.Debuglevel > 0 {
-
-		grpcLog.Info("*")
-		grpcLog.Info("* Kafka Config :")
-		grpcLog.Info(fmt.Sprintf("* Current path : %s", path))
-		grpcLog.Info(fmt.Sprintf("* Kafka File   : %s", fileName))
-		grpcLog.Info("*")
-
+	if vGeneral.Max_refund_delay == "" {
+		vGeneral.Max_refund_delay = "24h"
 	}
+	vRefundDelay, err = time.ParseDuration(vGeneral.Max_refund_delay)
+	if err != nil || vRefundDelay < time.Second {
+		grpcLog.Fatalln("Invalid Max_refund_delay: ", vGeneral.Max_refund_delay)
 
-	vKafka.Sasl_password = os.Getenv("Sasl_password")
-	vKafka.Sasl_username = os.Getenv("Sasl_username")
-
-	if vGeneral.EchoConfig == 1 {
-		printKafkaConfig(vKafka)
 	}
 
-	return vKafka
-}
-
-func loadMongoProps(params ...string) types.TMongodb {
-
-	vMongodb := types.TMongodb{}
-	env := "dev"
-	if len(params) > 0 {
-		env = params[0]
+	if vGeneral.TimeCompression <= 0 {
+		vGeneral.TimeCompression = 1
 	}
 
-	path, err := os.Getwd()
-	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Problem retrieving current path: %s", err))
-		os.Exit(1)
-
+	if vGeneral.Currency == "" {
+		vGeneral.Currency = "ZAR"
 	}
 
-	//	fileName := fmt.Sprintf("%s/%s_app.json", path, env)
-	fileName := fmt.Sprintf("%s/%s_mongo.json", path, env)
-	err = gonfig.GetConf(fileName, &vMongodb)
-	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Error Reading Mongo File: %s", err))
-		os.Exit(1)
-
+	if len(vGeneral.Tenders) == 0 {
+		vGeneral.Tenders = map[string]float64{"card": 1}
 	}
 
-	vGeneral.MongoConfigFile = fileName
-
-	if vGeneral.Debuglevel > 0 {
-
-		grpcLog.Info("*")
-		grpcLog.Info("* Mongo Config :")
-		grpcLog.Info(fmt.Sprintf("* Current path : %s", path))
-		grpcLog.Info(fmt.Sprintf("* Mongo File   : %s", fileName))
-		grpcLog.Info("*")
-
+	if vGeneral.Max_tenders < 2 {
+		vGeneral.Max_tenders = 2
 	}
 
-	vMongodb.Username = os.Getenv("mongo_username")
-	vMongodb.Password = os.Getenv("mongo_password")
-
-	if vMongodb.Username != "" {
+	if vGeneral.Max_retries < 0 {
+		vGeneral.Max_retries = 0
+	}
 
-		vMongodb.Uri = fmt.Sprintf("%s://%s:%s@%s&w=majority", vMongodb.Root, vMongodb.Username, vMongodb.Password, vMongodb.Url)
+	if vGeneral.Home_store_share <= 0 || vGeneral.Home_store_share > 1 {
+		vGeneral.Home_store_share = 0.8
+	}
 
-	} else {
+	if vGeneral.BackfillTps <= 0 {
+		vGeneral.BackfillTps = 1
+	}
 
-		vMongodb.Uri = fmt.Sprintf("%s://%s&w=majority", vMongodb.Root, vMongodb.Url)
+	if vGeneral.Output_path != "" {
+		vGeneral.Output_path = fmt.Sprintf("%s%s%s", vGeneral.CurrentPath, pathSep, vGeneral.Output_path)
 	}
 
 	if vGeneral.EchoConfig == 1 {
-		printMongoConfig(vMongodb)
+		grpcLog.Infoln("*")
+		grpcLog.Infoln("* Config:")
+		grpcLog.Infoln("* Current path:", vGeneral.CurrentPath)
+		grpcLog.Infoln("* Config File :", fileName)
+		grpcLog.Infoln("*")
+
+		printConfig(vGeneral)
 	}
 
-	return vMongodb
+	return vGeneral
 }
 
 func loadSeed(fileName string) types.TPSeed {
@@ -307,197 +375,217 @@ func loadSeed(fileName string) types.TPSeed {
 	return vSeed
 }
 
-func printConfig(vGeneral types.Tp_general) {
+// The distributions basket size, quantity and payment delay are drawn from
+func buildDistributions() {
 
-	grpcLog.Info("****** General Parameters *****")
-	grpcLog.Info("*")
-	grpcLog.Info("* Hostname is\t\t\t", vGeneral.Hostname)
-	grpcLog.Info("* OS is \t\t\t", vGeneral.OSName)
-	grpcLog.Info("*")
-	grpcLog.Info("* Debug Level is\t\t", vGeneral.Debuglevel)
-	grpcLog.Info("*")
-	grpcLog.Info("* Sleep Duration is\t\t", vGeneral.Sleep)
-	grpcLog.Info("* Test Batch Size is\t\t", vGeneral.Testsize)
-	grpcLog.Info("* Echo Seed is\t\t", vGeneral.EchoSeed)
-	grpcLog.Info("* Seed File is\t\t", vGeneral.SeedFile)
-	grpcLog.Info("* Json to File is\t\t", vGeneral.Json_to_file)
-	if vGeneral.Json_to_file == 1 {
-		grpcLog.Infoln("* Output path\t\t\t", vGeneral.Output_path)
+	build := func(name string, cfg types.TDistribution, min int, max int) distribution.Distribution {
+		d, err := distribution.New(cfg, min, max)
+		if err != nil {
+			grpcLog.Fatalln(fmt.Sprintf("Distributions %s configuration error: %s", name, err))
+
+		}
+		return d
 	}
-	grpcLog.Info("* Kafka Enabled is\t\t", vGeneral.KafkaEnabled)
-	grpcLog.Info("* Mongo Enabled is\t\t", vGeneral.MongoAtlasEnabled)
 
-	grpcLog.Info("*")
-	grpcLog.Info("*******************************")
+	vBasketSize = build("BasketSize", vGeneral.Distributions.BasketSize, 1, vGeneral.Max_items_basket)
+	vQuantity = build("Quantity", vGeneral.Distributions.Quantity, 1, vGeneral.Max_quantity)
+	vPayDelay = build("PaymentDelay", vGeneral.Distributions.PaymentDelay, 0, int(vPaymentDelay/time.Second))
+}
 
-	grpcLog.Info("")
+// Trading hours, timezone and closed dates of every seed store
+func compileStores(vSeed types.TPSeed) []*hours.Store {
 
-}
+	stores := make([]*hours.Store, len(vSeed.Stores))
+	for i, st := range vSeed.Stores {
+		h, err := hours.Compile(st)
+		if err != nil {
+			grpcLog.Fatalln("Error in Seed File: ", err)
 
-// print some more configurations
-func printKafkaConfig(vKafka types.TKafka) {
+		}
+		stores[i] = h
+	}
 
-	fmt.Printf("xxxxxxxxxxxxxxxxxxx")
+	return stores
+}
 
-	grpcLog.Info("****** Kafka Connection Parameters *****")
-	grpcLog.Info("*")
-	grpcLog.Info("* Kafka bootstrap Server is\t", vKafka.Bootstrapservers)
-	grpcLog.Info("* Kafka schema Registry is\t", vKafka.SchemaRegistryURL)
-	grpcLog.Info("* Kafka Basket Topic is\t", vKafka.BasketTopicname)
-	grpcLog.Info("* Kafka Payment Topic is\t", vKafka.PaymentTopicname)
-	grpcLog.Info("* Kafka # Parts is\t\t", vKafka.Numpartitions)
-	grpcLog.Info("* Kafka Rep Factor is\t\t", vKafka.Replicationfactor)
-	grpcLog.Info("* Kafka Retension is\t\t", vKafka.Retension)
-	grpcLog.Info("* Kafka ParseDuration is\t", vKafka.Parseduration)
-
-	grpcLog.Info("* Kafka SASL Mechanism is\t", vKafka.Sasl_mechanisms)
-	grpcLog.Info("* Kafka SASL Username is\t", vKafka.Sasl_username)
+// What every seed store sells, by popularity and at what price
+func buildCatalogs(vSeed types.TPSeed) []*catalog.Catalog {
 
-	grpcLog.Info("*")
-	grpcLog.Info("* Kafka Flush Size is\t\t", vKafka.Flush_interval)
-	grpcLog.Info("*")
-	grpcLog.Info("*******************************")
+	catalogs, err := catalog.Build(vSeed)
+	if err != nil {
+		grpcLog.Fatalln("Error in Seed File: ", err)
 
-	grpcLog.Info("")
+	}
 
+	return catalogs
 }
 
-// print some more configurations
-func printMongoConfig(vMongodb types.TMongodb) {
+// How every seed store gets paid, its own tenders or else the Tenders from *_app.json
+func buildTenders(vSeed types.TPSeed) []*tender.Mix {
 
-	grpcLog.Info("*")
-	grpcLog.Info("****** MongoDB Connection Parameters *****")
-	grpcLog.Info("*")
+	mixes := make([]*tender.Mix, len(vSeed.Stores))
+	for i, st := range vSeed.Stores {
 
-	grpcLog.Info("* Mongo URL is\t\t", vMongodb.Url)
-	grpcLog.Info("* Mongo Port is\t\t", vMongodb.Port)
-	grpcLog.Info("* Mongo DataStore is\t\t", vMongodb.Datastore)
-	grpcLog.Info("* Mongo Username is\t\t", vMongodb.Username)
-	grpcLog.Info("* Mongo Basket Collection is\t", vMongodb.Basketcollection)
-	grpcLog.Info("* Mongo Payment Collection is\t", vMongodb.Paymentcollection)
-	grpcLog.Info("* Mongo Batch szie is\t\t", vMongodb.Batch_size)
+		weights := st.Tenders
+		if len(weights) == 0 {
+			weights = vGeneral.Tenders
+		}
 
-	grpcLog.Info("*")
-	grpcLog.Info("*******************************")
+		m, err := tender.NewMix(weights)
+		if err != nil {
+			grpcLog.Fatalln(fmt.Sprintf("Tenders of store %s (%s): %s", st.Id, st.Name, err))
 
-	grpcLog.Info("")
+		}
+		mixes[i] = m
+	}
 
+	return mixes
 }
 
-// Create Kafka topic if not exist, using admin client
-func CreateTopic(props types.TKafka) {
+// Currency and tax rules of every seed store, its own or else Currency and Vatrate from *_app.json
+func compileTaxes(vSeed types.TPSeed) []*tax.Rules {
 
-	// we using kafka aliased to cpkafka as we've created our own kafka class located in internal/kafka
-	cm := cpkafka.ConfigMap{
-		"bootstrap.servers":       props.Bootstrapservers,
-		"broker.version.fallback": "0.10.0.0",
-		"api.version.fallback.ms": 0,
-	}
-
-	if props.Sasl_mechanisms != "" {
-		cm["sasl.mechanisms"] = props.Sasl_mechanisms
-		cm["security.protocol"] = props.Security_protocol
-		cm["sasl.username"] = props.Sasl_username
-		cm["sasl.password"] = props.Sasl_password
+	rules := make([]*tax.Rules, len(vSeed.Stores))
+	for i, st := range vSeed.Stores {
 
-		if vGeneral.Debuglevel > 0 {
-			grpcLog.Info("* Security Authentifaction configured in ConfigMap")
+		ru, err := tax.Compile(st, vSeed, vGeneral.Vatrate, vGeneral.Currency)
+		if err != nil {
+			grpcLog.Fatalln("Error in Seed File: ", err)
 
 		}
+		rules[i] = ru
 	}
 
-	if vGeneral.Debuglevel > 0 {
-		grpcLog.Info("* Basic Client ConfigMap compiled")
-	}
+	return rules
+}
+
+// The seed file's affinity rules, products bought together
+func compileAffinities(vSeed types.TPSeed) []affinity.Rule {
 
-	adminClient, err := cpkafka.NewAdminClient(&cm)
+	rules, err := affinity.Compile(vSeed)
 	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Admin Client Creation Failed: %s", err))
-		os.Exit(1)
+		grpcLog.Fatalln("Error in Seed File: ", err)
 
 	}
 
-	if vGeneral.Debuglevel > 0 {
-		grpcLog.Info("* Admin Client Created Succeeded")
+	if vGeneral.Debuglevel > 0 && len(rules) > 0 {
+		grpcLog.Infoln("* Affinity rules              :", len(rules))
 
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	return rules
+}
+
+func compileRoster(vSeed types.TPSeed) *roster.Roster {
 
-	maxDuration, err := time.ParseDuration(props.Parseduration)
+	ro, err := roster.Compile(vSeed, vStores)
 	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Error Configuring maxDuration via ParseDuration: %s", props.Parseduration))
-		os.Exit(1)
+		grpcLog.Fatalln("Error in Seed File: ", err)
 
 	}
 
-	if vGeneral.Debuglevel > 0 {
-		grpcLog.Info("* Configured maxDuration via ParseDuration")
+	if vGeneral.Debuglevel > 0 && ro != nil {
+		grpcLog.Infoln("* Clerk rosters               :", len(vSeed.Clerks), "clerks")
 
 	}
 
-	// the topics should rather be passed in as a array... but we want to be specific - rather move this external
-	// and call this routine twice from a loop.
-	//
+	return ro
+}
 
-	// Basket topic
-	results, err := adminClient.CreateTopics(ctx,
-		[]cpkafka.TopicSpecification{{
-			Topic:             props.BasketTopicname,
-			NumPartitions:     props.Numpartitions,
-			ReplicationFactor: props.Replicationfactor}},
-		cpkafka.SetAdminOperationTimeout(maxDuration))
+func compilePromotions(vSeed types.TPSeed) []promo.Promotion {
 
+	promos, err := promo.Compile(vSeed)
 	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Problem during the topic creation: %v", err))
-		os.Exit(1)
-	}
+		grpcLog.Fatalln("Error in Seed File: ", err)
 
-	// Check for specific topic errors
-	for _, result := range results {
-		if result.Error.Code() != cpkafka.ErrNoError &&
-			result.Error.Code() != cpkafka.ErrTopicAlreadyExists {
-			grpcLog.Error(fmt.Sprintf("Topic Creation Failed for %s: %v", result.Topic, result.Error.String()))
-			os.Exit(1)
+	}
 
-		} else {
-			if vGeneral.Debuglevel > 0 {
-				grpcLog.Info(fmt.Sprintf("* Topic Creation Succeeded for %s", result.Topic))
+	if vGeneral.Debuglevel > 0 && len(promos) > 0 {
+		grpcLog.Infoln("* Promotions                  :", len(promos))
 
-			}
-		}
 	}
 
-	// Payment topic
-	results, err = adminClient.CreateTopics(ctx,
-		[]cpkafka.TopicSpecification{{
-			Topic:             props.PaymentTopicname,
-			NumPartitions:     props.Numpartitions,
-			ReplicationFactor: props.Replicationfactor}},
-		cpkafka.SetAdminOperationTimeout(maxDuration))
+	return promos
+}
+
+// The loyalty customers, from their own random source so the rest of the run's stream doesn't change with them
+func buildCustomers(vSeed types.TPSeed) *customer.Population {
 
+	population, err := customer.New(vSeed, vGeneral.Customers, rand.New(rand.NewSource(vGeneral.RandomSeed+1)))
 	if err != nil {
-		grpcLog.Error(fmt.Sprintf("Problem during the topic creation: %v", err))
-		os.Exit(1)
+		grpcLog.Fatalln("Error in Seed File: ", err)
+
 	}
 
-	// Check for specific topic errors
-	for _, result := range results {
-		if result.Error.Code() != cpkafka.ErrNoError &&
-			result.Error.Code() != cpkafka.ErrTopicAlreadyExists {
-			grpcLog.Error(fmt.Sprintf("Topic Creation Failed for %s: %v", result.Topic, result.Error.String()))
-			os.Exit(1)
+	if vGeneral.Debuglevel > 0 && population != nil {
+		grpcLog.Infoln("* Loyalty customers           :", population.Len())
 
-		} else {
-			if vGeneral.Debuglevel > 0 {
-				grpcLog.Info(fmt.Sprintf("* Topic Creation Succeeded for %s", result.Topic))
+	}
 
-			}
-		}
+	return population
+}
+
+func printConfig(vGeneral types.Tp_general) {
+
+	grpcLog.Info("****** General Parameters *****")
+	grpcLog.Info("*")
+	grpcLog.Info("* Hostname is\t\t\t", vGeneral.Hostname)
+	grpcLog.Info("* OS is \t\t\t", vGeneral.OSName)
+	grpcLog.Info("*")
+	grpcLog.Info("* Debug Level is\t\t", vGeneral.Debuglevel)
+	grpcLog.Info("*")
+	grpcLog.Info("* Sleep Duration is\t\t", vGeneral.Sleep)
+	grpcLog.Info("* Target TPS is\t\t", vGeneral.TargetTps)
+	for _, stage := range vGeneral.LoadProfile {
+		grpcLog.Info(fmt.Sprintf("* Load Profile Stage\t\t %s, %s, %.1f -> %.1f TPS", stage.Name, stage.Duration, stage.StartTps, stage.EndTps))
+	}
+	grpcLog.Info("* Traffic Hours are\t\t", vGeneral.TrafficShape.Hours)
+	grpcLog.Info("* Traffic Weekdays are\t", vGeneral.TrafficShape.Weekdays)
+	for _, st := range vGeneral.TrafficShape.Stores {
+		grpcLog.Info(fmt.Sprintf("* Traffic Store %s\t Hours %v, Weekdays %v", st.Id, st.Hours, st.Weekdays))
 	}
+	grpcLog.Info("* Backfill Start is\t\t", vGeneral.BackfillStart)
+	grpcLog.Info("* Backfill End is\t\t", vGeneral.BackfillEnd)
+	grpcLog.Info("* Backfill TPS is\t\t", vGeneral.BackfillTps)
+	grpcLog.Info("* Random Seed is\t\t", vGeneral.RandomSeed)
+	grpcLog.Info("* Shutdown Timeout is\t", vGeneral.ShutdownTimeout)
+	grpcLog.Info("* Vat Rate is\t\t", vGeneral.Vatrate)
+	grpcLog.Info("* Currency is\t\t", vGeneral.Currency)
+	grpcLog.Info("* Invoice Format is\t\t", vGeneral.Invoice_format)
+	grpcLog.Info("* Tenders are\t\t", vGeneral.Tenders)
+	grpcLog.Info("* Split Tender is\t\t", vGeneral.Split_tender)
+	grpcLog.Info("* Max Tenders is\t\t", vGeneral.Max_tenders)
+	grpcLog.Info("* Declined Payment is\t", vGeneral.Declined_payment)
+	grpcLog.Info("* Failed Payment is\t\t", vGeneral.Failed_payment)
+	grpcLog.Info("* Max Retries is\t\t", vGeneral.Max_retries)
+	grpcLog.Info("* Partial Payment is\t", vGeneral.Partial_payment)
+	grpcLog.Info("* Missing Payment is\t", vGeneral.Missing_payment)
+	grpcLog.Info("* Max Payment Delay is\t", vGeneral.Max_payment_delay)
+	grpcLog.Info("* Refund Rate is\t\t", vGeneral.Refund_rate)
+	grpcLog.Info("* Max Refund Delay is\t", vGeneral.Max_refund_delay)
+	grpcLog.Info("* Customers is\t\t", vGeneral.Customers)
+	grpcLog.Info("* Loyalty Share is\t\t", vGeneral.Loyalty_share)
+	grpcLog.Info("* Home Store Share is\t", vGeneral.Home_store_share)
+	grpcLog.Info(fmt.Sprintf("* Basket Size Dist is\t %+v", vGeneral.Distributions.BasketSize))
+	grpcLog.Info(fmt.Sprintf("* Quantity Dist is\t\t %+v", vGeneral.Distributions.Quantity))
+	grpcLog.Info(fmt.Sprintf("* Payment Delay Dist is\t %+v", vGeneral.Distributions.PaymentDelay))
+	grpcLog.Info("* Delay Payments is\t\t", vGeneral.DelayPayments)
+	grpcLog.Info("* Time Compression is\t", vGeneral.TimeCompression)
+	grpcLog.Info("* Workers is\t\t\t", vGeneral.Workers)
+	grpcLog.Info("* Metrics Port is\t\t", vGeneral.MetricsPort)
+	grpcLog.Info("* Pushgateway is\t\t", vGeneral.PushgatewayURL)
+	grpcLog.Info("* Test Batch Size is\t\t", vGeneral.Testsize)
+	grpcLog.Info("* Echo Seed is\t\t", vGeneral.EchoSeed)
+	grpcLog.Info("* Seed File is\t\t", vGeneral.SeedFile)
+	grpcLog.Info("* Json to File is\t\t", vGeneral.Json_to_file)
+	grpcLog.Infoln("* Output path\t\t\t", vGeneral.Output_path)
+	grpcLog.Info("* Kafka Enabled is\t\t", vGeneral.KafkaEnabled)
+	grpcLog.Info("* Mongo Enabled is\t\t", vGeneral.MongoAtlasEnabled)
+	grpcLog.Info("* Sinks are\t\t\t", vGeneral.Sinks)
+
+	grpcLog.Info("*")
+	grpcLog.Info("*******************************")
 
-	adminClient.Close()
 	grpcLog.Info("")
 
 }
@@ -536,658 +624,1201 @@ func xprettyJSON(ms string) string {
 	return string(result)
 }
 
-func JsonToBson(message []byte) ([]byte, error) {
-	reader, err := bsonrw.NewExtJSONValueReader(bytes.NewReader(message), true)
-	if err != nil {
-		return []byte{}, err
+// Initialise the single random source used for every pick we make, if no seed was configured we take one from
+// the clock and log it so the run can still be replayed afterwards.
+func initRandom() {
+
+	if vSeedOverride != nil {
+		vGeneral.RandomSeed = *vSeedOverride
 	}
-	buf := &bytes.Buffer{}
-	writer, _ := bsonrw.NewBSONValueWriter(buf)
-	err = bsonrw.Copier{}.CopyDocument(writer, reader)
-	if err != nil {
-		return []byte{}, err
+
+	if vGeneral.RandomSeed == 0 {
+		vGeneral.RandomSeed = time.Now().UnixNano()
+	}
+
+	vRandom = rand.New(rand.NewSource(vGeneral.RandomSeed))
+
+	// Fake Data etc, not used much here though, gofakeit draws from the global math/rand so seed it as well
+	// https://github.com/brianvoe/gofakeit
+	// https://pkg.go.dev/github.com/brianvoe/gofakeit
+	gofakeit.Seed(vGeneral.RandomSeed)
+
+	grpcLog.Infoln("* Random Seed                 :", vGeneral.RandomSeed)
+
+}
+
+// Random value between min and max, both inclusive, same contract as gofakeit.Number()
+func randomNumber(r *rand.Rand, min int, max int) int {
+
+	if min >= max {
+		return min
 	}
-	marshaled := buf.Bytes()
-	return marshaled, nil
+	return r.Intn(max-min+1) + min
 }
 
-// https://stackoverflow.com/questions/18390266/how-can-we-truncate-float64-type-to-a-particular-precision
-func round(num float64) int {
-	return int(num + math.Copysign(0.5, num))
+// Index picked at random in proportion to the weights, -1 if they are all 0
+func weightedIndex(r *rand.Rand, weights []float64) int {
+
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		return -1
+	}
+
+	x := r.Float64() * total
+	for i, w := range weights {
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+
+	return len(weights) - 1
 }
-func toFixed(num float64, precision int) float64 {
-	output := math.Pow(10, float64(precision))
-	return float64(round(num*output)) / output
+
+// UUID drawn from our seeded random source, so invoice numbers etc. are replayable
+func randomUUID(r *rand.Rand) string {
+
+	return uuid.Must(uuid.NewRandomFromReader(r)).String()
 }
 
-func constructFakeBasket() (pb_Basket types.Pb_Basket, eventTimestamp time.Time, storeName string, err error) {
+// Event time as stamped on the documents, in the store's own timezone if it has one, otherwise local time + TimeOffset
+func formatEventTime(t time.Time, loc *time.Location) string {
 
-	// Fake Data etc, not used much here though
-	// https://github.com/brianvoe/gofakeit
-	// https://pkg.go.dev/github.com/brianvoe/gofakeit
+	if loc == nil {
+		return t.Local().Format("2006-01-02T15:04:05.000") + vGeneral.TimeOffset
+	}
+	return t.In(loc).Format("2006-01-02T15:04:05.000-07:00")
+}
 
-	gofakeit.Seed(0)
+// Build a basket sold at eventTimestamp, returns a nil basket if no store is open at that time, nStoreId is
+// the varSeed.Stores index of the store it was sold at
+func constructFakeBasket(r *rand.Rand, eventTimestamp time.Time) (pb_Basket *types.Pb_Basket, nStoreId int, err error) {
 
 	var store types.Idstruct
-	var clerk types.Idstruct
 	if vGeneral.Store == 0 {
-		// Determine how many Stores we have in seed file,
+		// Determine how many Stores we have in seed file, and which of them are open,
 		// and build the 2 structures from that viewpoint
 		storeCount := len(varSeed.Stores) - 1
-		nStoreId := gofakeit.Number(0, storeCount)
-		store.Id = varSeed.Stores[nStoreId].Id
-		store.Name = varSeed.Stores[nStoreId].Name
+		weights := make([]float64, len(varSeed.Stores))
+		allOpen := true
+		for i, st := range varSeed.Stores {
+			if !trades(i, eventTimestamp) {
+				allOpen = false
+				continue
+			}
+
+			weights[i] = 1
+			if vShape != nil && vShape.PerStore() {
+				// busier stores, as per their own traffic curve in their own time, get more of the baskets
+				local := eventTimestamp
+				if l := vStores[i].Location(); l != nil {
+					local = local.In(l)
+				}
+				weights[i] = vShape.StoreFactor(st.Id, local)
+			}
+		}
+
+		if allOpen && (vShape == nil || !vShape.PerStore()) {
+			nStoreId = randomNumber(r, 0, storeCount)
+
+		} else if nStoreId = weightedIndex(r, weights); nStoreId < 0 {
+			// nobody's trading
+			return nil, -1, nil
+
+		}
 
 	} else {
-		// We specified a specific store
-		store.Id = varSeed.Stores[vGeneral.Store].Id
-		store.Name = varSeed.Stores[vGeneral.Store].Name
+		// We specified a specific store, only sells when it's open
+		nStoreId = vGeneral.Store
+		if !trades(nStoreId, eventTimestamp) {
+			return nil, -1, nil
+		}
 
 	}
+	store.Id = varSeed.Stores[nStoreId].Id
+	store.Name = varSeed.Stores[nStoreId].Name
+	loc := vStores[nStoreId].Location()
 
-	// Determine how many Clerks we have in seed file,
-	clerkCount := len(varSeed.Clerks) - 1
-	nClerkId := gofakeit.Number(0, clerkCount)
-	clerk.Id = varSeed.Clerks[nClerkId].Id
-	clerk.Name = varSeed.Clerks[nClerkId].Name
+	clerk := pickClerk(r, nStoreId, eventTimestamp)
 
 	// Uniqiue reference to the basket/sale
-	txnId := uuid.New().String()
+	txnId := randomUUID(r)
 
 	// time that everything happened, the 1st as a Unix Epoc time representation,
 	// the 2nd in nice human readable milli second representation.
-	eventTimestamp = time.Now()
-	eventTime := eventTimestamp.Format("2006-01-02T15:04:05.000") + vGeneral.TimeOffset
+	eventTime := formatEventTime(eventTimestamp, loc)
 
-	// How many potential products do we have
-	productCount := len(varSeed.Products) - 1
-	// now pick from array a random products to add to basket, by using 1 as a start point we ensure we always have at least 1 item.
-	nBasketItems := gofakeit.Number(1, vGeneral.Max_items_basket)
+	// a loyalty customer, or an anonymous sale
+	var shopper *customer.Customer
+	if vCustomers != nil && vGeneral.Loyalty_share > 0 && r.Float64() < vGeneral.Loyalty_share {
+		shopper = vCustomers.Pick(r, nStoreId, vGeneral.Home_store_share)
+	}
 
-	nett_amount := 0.0
+	// What this store sells
+	storeCatalog := vCatalogs[nStoreId]
+	// now pick from array a random products to add to basket, by using 1 as a start point we ensure we always have at least 1 item.
+	nBasketItems := vBasketSize.Sample(r)
+	if shopper != nil {
+		// big spenders fill bigger baskets
+		nBasketItems = int(math.Round(float64(nBasketItems) * shopper.Spend()))
+		if nBasketItems < 1 {
+			nBasketItems = 1
+		}
+		if nBasketItems > vGeneral.Max_items_basket {
+			nBasketItems = vGeneral.Max_items_basket
+		}
+	}
 
 	var BasketItems []*types.BasketItem
+	var anchors []types.TProductStruct
 
-	for count := 0; count < nBasketItems; count++ {
-
-		productId := gofakeit.Number(0, productCount)
+	addItem := func(product types.TProductStruct) {
 
-		quantity := gofakeit.Number(1, vGeneral.Max_quantity)
-		price := varSeed.Products[productId].Price
+		quantity := vQuantity.Sample(r)
 
 		BasketItem := &types.BasketItem{
-			Id:       varSeed.Products[productId].Id,
-			Name:     varSeed.Products[productId].Name,
-			Brand:    varSeed.Products[productId].Brand,
-			Category: varSeed.Products[productId].Category,
-			Price:    varSeed.Products[productId].Price,
-			Quantity: int32(quantity),
+			Id:         product.Id,
+			Name:       product.Name,
+			Brand:      product.Brand,
+			Category:   product.Category,
+			Price:      product.Price,
+			Quantity:   int32(quantity),
+			PriceMinor: int64(money.FromFloat(product.Price)),
 		}
 		BasketItems = append(BasketItems, BasketItem)
 
-		nett_amount = nett_amount + price*float64(quantity)
+	}
+
+	for count := 0; count < nBasketItems; count++ {
+
+		product := storeCatalog.Pick(r)
+		anchors = append(anchors, product)
+		addItem(product)
+
+	}
+
+	// and what tends to go with them, see the seed file's affinities
+	if len(vAffinities) > 0 {
+		for _, product := range affinity.Apply(r, vAffinities, anchors, storeCatalog) {
+			addItem(product)
+		}
+	}
 
+	// whatever is on promotion at the store today
+	var discount_amount money.Amount
+	if len(vPromotions) > 0 {
+		local := eventTimestamp.Local()
+		if loc != nil {
+			local = eventTimestamp.In(loc)
+		}
+		discount_amount = promo.Apply(vPromotions, store.Id, local, BasketItems)
 	}
 
-	nett_amount = toFixed(nett_amount, 2)
-	vat_amount := toFixed(nett_amount*vGeneral.Vatrate, 2) // sales tax
-	total_amount := toFixed(nett_amount+vat_amount, 2)
-	terminalPoint := gofakeit.Number(0, 20)
+	// sales tax, as per the store's tax rules, in cents so the total is exactly nett + vat
+	storeTax := vTaxes[nStoreId]
+	nett_amount, vat_amount, taxes := storeTax.Apply(BasketItems)
+	total_amount := nett_amount + vat_amount
+	// the till it's rung up on, one of the store's own when the seed file lists them
+	var terminalPoint string
+	if terminals := varSeed.Stores[nStoreId].Terminals; len(terminals) > 0 {
+		terminalPoint = terminals[r.Intn(len(terminals))]
+	} else {
+		terminalPoint = strconv.Itoa(randomNumber(r, 0, 20))
+	}
 
-	pb_Basket = types.Pb_Basket{
+	pb_Basket = &types.Pb_Basket{
 		InvoiceNumber: txnId,
 		SaleDateTime:  eventTime,
 		SaleTimestamp: fmt.Sprint(eventTimestamp.UnixMilli()),
 		Store:         &store,
-		Clerk:         &clerk,
-		TerminalPoint: strconv.Itoa(terminalPoint),
+		Clerk:         clerk,
+		TerminalPoint: terminalPoint,
 		BasketItems:   BasketItems,
-		Nett:          nett_amount,
-		Vat:           vat_amount,
-		Total:         total_amount,
+		Nett:          nett_amount.Float(),
+		Vat:           vat_amount.Float(),
+		Total:         total_amount.Float(),
+		Discount:      discount_amount.Float(),
+		NettMinor:     int64(nett_amount),
+		VatMinor:      int64(vat_amount),
+		TotalMinor:    int64(total_amount),
+		DiscountMinor: int64(discount_amount),
+		Currency:      storeTax.Currency(),
+		Country:       storeTax.Country(),
+		TaxInclusive:  storeTax.Inclusive(),
+		Taxes:         taxes,
 	}
-
-	return pb_Basket, eventTimestamp, store.Name, nil
-}
-
-func constructPayments(txnId string, eventTimestamp time.Time, total_amount float64) (pb_Payment types.Pb_Payment) {
-
-	// We're saying payment can be now up to 5min and 59 seconds later
-	payTimestamp := eventTimestamp.Local().Add(time.Minute*time.Duration(gofakeit.Number(0, 5)) + time.Second*time.Duration(gofakeit.Number(0, 59)))
-	payTime := payTimestamp.Format("2006-01-02T15:04:05.000") + vGeneral.TimeOffset
-
-	pb_Payment = types.Pb_Payment{
-		InvoiceNumber:    txnId,
-		PayDateTime:      payTime,
-		PayTimestamp:     fmt.Sprint(payTimestamp.UnixMilli()),
-		Paid:             total_amount,
-		FinTransactionID: uuid.New().String(),
+	if shopper != nil {
+		pb_Basket.Customer = shopper.Proto()
 	}
 
-	return pb_Payment
+	return pb_Basket, nStoreId, nil
 }
 
-// Big worker... This is where all the magic is called from, ha ha.
-func runLoader(arg string) {
-
-	var err error
-	var f_basket *os.File
-	var f_pmnt *os.File
-	var basketcol *mongo.Collection
-	var paymentcol *mongo.Collection
-
-	// Initialize the vGeneral struct variable - This holds our configuration settings.
-	vGeneral = loadConfig(arg)
-
-	// Lets get Seed Data from the specified seed file
-	varSeed = loadSeed(vGeneral.SeedFile)
-
-	// Initiale the vKafka struct variable - This holds our Confluent Kafka configuration settings.
-	// if Kafka is enabled then create the confluent kafka connection session/objects
-	if vGeneral.KafkaEnabled == 1 {
+// Build the payments for a basket sold at store nStoreId, one per tender, a split tender basket gets more than one,
+// as does a declined or failed tender that is tried again. payDelays says how long after the sale each was made.
+// A basket whose payment never arrives gets none, one whose customer gave up or only paid part is left short.
+func constructPayments(r *rand.Rand, txnId string, eventTimestamp time.Time, nStoreId int, total_amount money.Amount) (pb_Payments []*types.Pb_Payment, payDelays []time.Duration) {
 
-		vKafka = loadKafka(arg)
+	loc := vStores[nStoreId].Location()
 
-		fmt.Println("Mechanism", vKafka.Sasl_mechanisms)
-		fmt.Println("Username", vKafka.Sasl_username)
-		fmt.Println("Password", vKafka.Sasl_password)
-		fmt.Println("Broker", vKafka.Bootstrapservers)
+	// We're saying payment can be now up to Max_payment_delay later, to the second
+	payDelay := time.Second * time.Duration(vPayDelay.Sample(r))
 
-		// Lets make sure the topic/s exist
-		CreateTopic(vKafka)
+	nTenders := 1
+	if vGeneral.Split_tender > 0 && r.Float64() < vGeneral.Split_tender {
+		nTenders = randomNumber(r, 2, vGeneral.Max_tenders)
+	}
+	amounts := tender.Split(r, total_amount, nTenders)
 
-		// --
-		// Create Producer instance
-		// https://docs.confluent.io/current/clients/confluent-kafka-go/index.html#NewProducer
+	if vGeneral.Missing_payment > 0 && r.Float64() < vGeneral.Missing_payment {
+		return nil, nil
+	}
 
-		if vGeneral.Debuglevel > 0 {
-			grpcLog.Info("**** Configure Client Kafka Connection ****")
-			grpcLog.Info("*")
-			grpcLog.Info(fmt.Sprintf("* Kafka bootstrap Server is %s", vKafka.Bootstrapservers))
-			if vKafka.SchemaRegistryURL != "" {
-				grpcLog.Info(fmt.Sprintf("* Schema Registry URL is    %s", vKafka.SchemaRegistryURL))
-			}
-		}
+	for i, amount := range amounts {
 
-		cm := cpkafka.ConfigMap{
-			"bootstrap.servers":       vKafka.Bootstrapservers,
-			"broker.version.fallback": "0.10.0.0",
-			"api.version.fallback.ms": 0,
-			"client.id":               vGeneral.Hostname,
+		// the next tender follows a little after the previous one
+		if i > 0 {
+			payDelay += time.Second * time.Duration(randomNumber(r, 5, 30))
 		}
 
-		if vGeneral.Debuglevel > 0 {
-			grpcLog.Info("* Basic Client ConfigMap compiled")
+		finTransactionID := randomUUID(r)
+		tenderType := vTenders[nStoreId].Pick(r)
 
+		// the last tender might only cover part of what is due
+		paid, status := amount, "approved"
+		if i == len(amounts)-1 && vGeneral.Partial_payment > 0 && r.Float64() < vGeneral.Partial_payment {
+			paid, status = amount.Mul(float64(randomNumber(r, 10, 90))/100), "partial"
 		}
 
-		if vKafka.Sasl_mechanisms != "" {
-			cm["sasl.mechanisms"] = vKafka.Sasl_mechanisms
-			cm["security.protocol"] = vKafka.Security_protocol
-			cm["sasl.username"] = vKafka.Sasl_username
-			cm["sasl.password"] = vKafka.Sasl_password
-			if vGeneral.Debuglevel > 0 {
-				grpcLog.Info("* Security Authentifaction configured in ConfigMap")
+		for attempt := 1; ; attempt++ {
 
+			if attempt > 1 {
+				payDelay += time.Second * time.Duration(randomNumber(r, 10, 60))
+				finTransactionID = randomUUID(r)
+			}
+			payTimestamp := eventTimestamp.Add(payDelay)
+			payTime := formatEventTime(payTimestamp, loc)
+
+			pb_Payment := &types.Pb_Payment{
+				InvoiceNumber:    txnId,
+				PayDateTime:      payTime,
+				PayTimestamp:     fmt.Sprint(payTimestamp.UnixMilli()),
+				Paid:             paid.Float(),
+				FinTransactionID: finTransactionID,
+				TenderType:       tenderType,
+				TenderSeq:        int32(i + 1),
+				TenderCount:      int32(len(amounts)),
+				Status:           status,
+				Attempt:          int32(attempt),
+				AmountDue:        amount.Float(),
+				Currency:         vTaxes[nStoreId].Currency(),
+				PaidMinor:        int64(paid),
+				AmountDueMinor:   int64(amount),
 			}
-		}
-
-		// internal/kafka/producer.go
-		producer, err := kafka.NewProducer(cm, vKafka.SchemaRegistryURL)
-		defer producer.Close()
 
-		// Check for errors in creating the Producer
-		if err != nil {
-			grpcLog.Error(fmt.Sprintf("😢Oh noes, there's an error creating the Producer! %s", err))
-
-			if ke, ok := err.(cpkafka.Error); ok {
-				switch ec := ke.Code(); ec {
-				case cpkafka.ErrInvalidArg:
-					grpcLog.Error(fmt.Sprintf("😢 Can't create the producer because you've configured it wrong (code: %d)!\n\t%v\n\nTo see the configuration options, refer to https://github.com/edenhill/librdkafka/blob/master/CONFIGURATION.md", ec, err))
-				default:
-					grpcLog.Error(fmt.Sprintf("😢 Can't create the producer (Kafka error code %d)\n\tError: %v\n", ec, err))
-				}
+			if outcome, reason := attemptOutcome(r, tenderType); outcome != "" {
+				pb_Payment.Paid, pb_Payment.PaidMinor = 0, 0
+				pb_Payment.Status = outcome
+				pb_Payment.Reason = reason
+			}
 
-			} else {
-				// It's not a kafka.Error
-				grpcLog.Error(fmt.Sprintf("😢 Oh noes, there's a generic error creating the Producer! %v", err.Error()))
+			switch pb_Payment.TenderType {
+			case "cash":
+				tendered, change := tender.Cash(r, money.Amount(pb_Payment.PaidMinor))
+				pb_Payment.Tendered, pb_Payment.Change = tendered.Float(), change.Float()
+				pb_Payment.TenderedMinor, pb_Payment.ChangeMinor = int64(tendered), int64(change)
+			case "card":
+				pb_Payment.Card = tender.Card(r)
+			case "eft":
+				pb_Payment.Reference = fmt.Sprintf("EFT%010d", r.Int63n(1e10))
+			case "voucher":
+				pb_Payment.Reference = fmt.Sprintf("V%012d", r.Int63n(1e12))
+			case "loyalty":
+				pb_Payment.PointsRedeemed = pb_Payment.PaidMinor
 			}
-			// call it when you know it's broken
-			os.Exit(1)
 
-		}
+			pb_Payments = append(pb_Payments, pb_Payment)
+			payDelays = append(payDelays, payDelay)
 
-		if vGeneral.Debuglevel > 0 {
-			grpcLog.Info("* Created Kafka Producer instance :")
-			grpcLog.Info("")
+			if pb_Payment.Status != "declined" && pb_Payment.Status != "failed" {
+				break
+			}
+
+			// out of retries, the customer gives up and the rest of the basket stays unpaid
+			if attempt > vGeneral.Max_retries {
+				return pb_Payments, payDelays
+			}
 		}
 	}
 
-	// We will use this to remember when we last flushed the kafka queues.
-	vFlush := 0
+	return pb_Payments, payDelays
+}
 
-	if vGeneral.MongoAtlasEnabled == 1 {
+// Whether an attempt to pay with tenderType is declined or fails, and why, "" when it goes through. Cash is
+// never turned down.
+func attemptOutcome(r *rand.Rand, tenderType string) (status string, reason string) {
 
-		vMongodb = loadMongoProps(arg)
+	if tenderType == "cash" {
+		return "", ""
+	}
+	if vGeneral.Failed_payment > 0 && r.Float64() < vGeneral.Failed_payment {
+		return "failed", tender.FailureReason(r)
+	}
+	if vGeneral.Declined_payment > 0 && r.Float64() < vGeneral.Declined_payment {
+		return "declined", tender.DeclineReason(r)
+	}
+	return "", ""
+}
 
-		serverAPI := options.ServerAPI(options.ServerAPIVersion1)
+// Build a refund for some of the basket's items, brought back to the store it was sold at up to Max_refund_delay
+// after the sale, refundDelay says how long after
+func constructRefund(r *rand.Rand, pb_Basket *types.Pb_Basket, eventTimestamp time.Time, nStoreId int, paid money.Amount, paidDelay time.Duration) (pb_Refund *types.Pb_Refund, refundDelay time.Duration) {
 
-		opts := options.Client().ApplyURI(vMongodb.Uri).SetServerAPIOptions(serverAPI)
+	// counted from when it was paid for, and the store has to be trading with a clerk on shift to take it
+	refundDelay = paidDelay + time.Second*time.Duration(1+r.Int63n(int64(vRefundDelay/time.Second)))
+	refundTimestamp := nextTrading(nStoreId, eventTimestamp.Add(refundDelay))
+	refundDelay = refundTimestamp.Sub(eventTimestamp)
 
-		grpcLog.Infoln("* MongoDB URI Constructed: ", vMongodb.Uri)
+	pb_Refund = refund.Build(r, pb_Basket, vTaxes[nStoreId], paid)
+	if pb_Refund == nil {
+		return nil, 0
+	}
+	pb_Refund.RefundNumber = randomUUID(r)
+	pb_Refund.RefundDateTime = formatEventTime(refundTimestamp, vStores[nStoreId].Location())
+	pb_Refund.RefundTimestamp = fmt.Sprint(refundTimestamp.UnixMilli())
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		grpcLog.Infoln("* MongoDB Context Object Created")
+	// whoever is at the till then
+	pb_Refund.Clerk = pickClerk(r, nStoreId, refundTimestamp)
 
-		defer cancel()
+	return pb_Refund, refundDelay
+}
 
-		Mongoclient, err := mongo.Connect(ctx, opts)
-		if err != nil {
-			grpcLog.Fatal("Mongo Connect Failed: ", err)
+// Whether store nStoreId trades at t, it's open and, when it has a roster, one of its clerks is on shift
+func trades(nStoreId int, t time.Time) bool {
+	return vStores[nStoreId].Open(t) && (vRoster == nil || vRoster.OnShift(varSeed.Stores[nStoreId].Id, t))
+}
+
+// The first minute from t that store nStoreId trades, t itself if it does, as is if it doesn't within a month
+func nextTrading(nStoreId int, t time.Time) time.Time {
+
+	for next, end := t, t.AddDate(0, 1, 0); next.Before(end); next = next.Add(time.Minute) {
+		if trades(nStoreId, next) {
+			return next
 		}
-		grpcLog.Infoln("* MongoDB Client Connected")
+	}
+	return t
+}
 
-		defer func() {
-			if err = Mongoclient.Disconnect(ctx); err != nil {
-				grpcLog.Fatal("Mongo Disconected: ", err)
-			}
-		}()
+// Whether any store we sell from, vGeneral.Store or, when that is 0, any of them, trades at t
+func trading(t time.Time) bool {
 
-		// Ping the primary
-		if err := Mongoclient.Ping(ctx, readpref.Primary()); err != nil {
-			grpcLog.Fatal("There was a error creating the Client object, Ping failed: ", err)
+	if vGeneral.Store != 0 {
+		return trades(vGeneral.Store, t)
+	}
+	for i := range vStores {
+		if trades(i, t) {
+			return true
 		}
-		grpcLog.Infoln("* MongoDB Client Pinged")
+	}
+	return false
+}
 
-		// Create go routine to defer the closure
-		defer func() {
-			if err = Mongoclient.Disconnect(context.TODO()); err != nil {
-				grpcLog.Fatal("Mongo Disconected: ", err)
-			}
-		}()
+// The first minute after t that one of the stores we sell from trades, a month on if none does before then
+func nextOpen(t time.Time) time.Time {
 
-		// Define the Mongo Datastore
-		appLabDatabase := Mongoclient.Database(vMongodb.Datastore)
-		// Define the Mongo Collection Object
-		basketcol = appLabDatabase.Collection(vMongodb.Basketcollection)
-		paymentcol = appLabDatabase.Collection(vMongodb.Paymentcollection)
+	next, end := t.Truncate(time.Minute).Add(time.Minute), t.AddDate(0, 1, 0)
+	for ; next.Before(end) && !trading(next); next = next.Add(time.Minute) {
+	}
+	return next
+}
 
-		if vGeneral.Debuglevel > 0 {
-			grpcLog.Infoln("* MongoDB Datastore and Collections Intialized")
-			grpcLog.Infoln("*")
-		}
+// The clerk serving at store nStoreId at t, one of the store's own on shift as per the seed file's rosters,
+// or, when clerks aren't bound to stores, any of them
+func pickClerk(r *rand.Rand, nStoreId int, t time.Time) *types.Idstruct {
 
+	if vRoster != nil {
+		return vRoster.Pick(r, varSeed.Stores[nStoreId].Id, t)
 	}
 
-	//We've said we want to safe records to file so lets initiale the file handles etc.
+	// Determine how many Clerks we have in seed file,
+	clerkCount := len(varSeed.Clerks) - 1
+	nClerkId := randomNumber(r, 0, clerkCount)
+	return &types.Idstruct{Id: varSeed.Clerks[nClerkId].Id, Name: varSeed.Clerks[nClerkId].Name}
+}
+
+// The sinks we write to, either as listed in Sinks or, when that is empty, as flagged by the older
+// KafkaEnabled / MongoAtlasEnabled / Json_to_file settings.
+func enabledSinks() []string {
+
+	if len(vGeneral.Sinks) > 0 {
+		return vGeneral.Sinks
+	}
+
+	var names []string
+	if vGeneral.KafkaEnabled == 1 {
+		names = append(names, "kafka")
+	}
+	if vGeneral.MongoAtlasEnabled == 1 {
+		names = append(names, "mongo")
+	}
 	if vGeneral.Json_to_file == 1 {
+		names = append(names, "file")
+	}
 
-		// each time we run, and say we want to store the data created to disk, we create a pair of files for that run.
-		// this runId is used as the file name, prepended to either _basket.json or _pmnt.json
-		runId = uuid.New().String()
+	return names
+}
+
+// An opened sink and the name it was registered under
+type namedSink struct {
+	name string
+	sink.Sink
+}
 
-		// Open file -> Baskets
-		loc_basket := fmt.Sprintf("%s%s%s_%s.json", vGeneral.Output_path, pathSep, runId, "basket")
-		if vGeneral.Debuglevel > 2 {
-			grpcLog.Infoln("Basket File          :", loc_basket)
+// Create and open every enabled sink, see internal/sink
+func openSinks(arg string) []namedSink {
 
-		}
+	cfg := sink.Config{
+		Env:     arg,
+		General: &vGeneral,
+		Log:     grpcLog,
+	}
+
+	var sinks []namedSink
+	for i, name := range enabledSinks() {
+
+		// every sink writes from its own goroutine, so each gets its own random source, off the run's seed rather
+		// than vRandom so the baskets don't depend on the sinks (RandomSeed+1 is the customers')
+		cfg.Random = rand.New(rand.NewSource(vGeneral.RandomSeed + 2 + int64(i)))
 
-		f_basket, err = os.OpenFile(loc_basket, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		s, err := sink.New(name)
 		if err != nil {
-			grpcLog.Errorln("os.OpenFile error A", err)
-			panic(err)
+			grpcLog.Fatalln("Sink creation failed: ", err)
 
 		}
-		defer f_basket.Close()
 
-		// Open file -> Payment
-		loc_pmnt := fmt.Sprintf("%s%s%s_%s.json", vGeneral.Output_path, pathSep, runId, "pmnt")
-		if vGeneral.Debuglevel > 2 {
-			grpcLog.Infoln("Payment File         :", loc_basket)
+		if err = s.Open(cfg); err != nil {
+			grpcLog.Fatalln(fmt.Sprintf("Opening the %s sink failed: %s", name, err))
 
 		}
 
-		f_pmnt, err = os.OpenFile(loc_pmnt, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			grpcLog.Errorln("os.OpenFile error A", err)
-			panic(err)
+		if vGeneral.Debuglevel > 0 {
+			grpcLog.Infoln("* Sink opened                 :", name)
 
 		}
-		defer f_pmnt.Close()
-	}
 
-	// if set to 0 then we want it to simply just run and run and run. so lets give it a pretty big number
-	if vGeneral.Testsize == 0 {
-		vGeneral.Testsize = 10000000000000
+		sinks = append(sinks, namedSink{name: name, Sink: s})
 	}
 
-	if vGeneral.Debuglevel > 0 {
-		grpcLog.Info("**** LETS GO Processing ****")
-		grpcLog.Infoln("")
+	return sinks
+}
 
-	}
+// Wait for the sink writers to finish, then flush and close every sink, giving them at most ShutdownTimeout seconds
+// between them to do so, a write that blocks can't hang the shutdown
+func closeSinks(sinks []namedSink, writers *sync.WaitGroup) {
 
-	basketdocs := make([]interface{}, vMongodb.Batch_size)
-	paymentdocs := make([]interface{}, vMongodb.Batch_size)
-	msg_mongo_count := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
 
-	// this is to keep record of the total batch run time
-	vStart := time.Now()
-	for count := 0; count < vGeneral.Testsize; count++ {
+		writers.Wait()
 
-		reccount := fmt.Sprintf("%v", count+1)
+		// Push out whatever is still sitting in producer queues, Mongo batches and file buffers
+		for _, s := range sinks {
+			if err := s.Flush(); err != nil {
+				grpcLog.Errorln(fmt.Sprintf("Flushing the %s sink failed: %s", s.name, err))
 
-		if vGeneral.Debuglevel > 0 {
-			grpcLog.Infoln("")
-			grpcLog.Infoln("Record                        :", reccount)
+			}
+			if err := s.Close(); err != nil {
+				grpcLog.Errorln(fmt.Sprintf("Closing the %s sink failed: %s", s.name, err))
 
+			}
+			if deferred, ok := s.Sink.(sink.Deferred); ok && deferred.Defers() {
+				settle(s.name, deferred)
+			}
 		}
+	}()
+
+	timeout := time.Duration(vGeneral.ShutdownTimeout) * time.Second
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		grpcLog.Errorln(fmt.Sprintf("Sinks did not flush and close within %v, records may have been lost", timeout))
 
-		// We're going to time every record and push that to prometheus
-		txnStart := time.Now()
+	}
+}
+
+// A unit of work for the generator workers, seed gives every record its own random source so what we generate
+// doesn't depend on which worker picked the job up, or when.
+type job struct {
+	seq       int
+	seed      int64
+	start     time.Time
+	eventTime time.Time // when the sale happens, from vClock
+}
+
+// A generated sale on its way to the sinks, with DelayPayments the payments and refund follow later in records of
+// their own
+type record struct {
+	seq         int
+	start       time.Time
+	basket      *types.Pb_Basket
+	payments    []*types.Pb_Payment
+	refund      *types.Pb_Refund
+	store       int             // varSeed.Stores index of the basket's store
+	saleTime    time.Time       // when the sale happened
+	payDelays   []time.Duration // how long after the sale each payment happened
+	refundDelay time.Duration   // how long after the sale the refund happened
+}
+
+// generate builds the basket and its payment for every job, until the jobs channel is closed
+func generate(jobs <-chan job, records chan<- record) {
+
+	for j := range jobs {
+
+		r := rand.New(rand.NewSource(j.seed))
 
 		// Build an sales basket
-		pb_Basket, eventTimestamp, storeName, err := constructFakeBasket()
+		pb_Basket, nStoreId, err := constructFakeBasket(r, j.eventTime)
 		if err != nil {
-			os.Exit(1)
+			grpcLog.Fatalln("constructFakeBasket failed: ", err)
 
 		}
 
+		if pb_Basket == nil {
+			// every store is closed, nothing sold, sequence() still needs to hear about it
+			records <- record{seq: j.seq, start: j.start}
+			continue
+		}
+
 		// Build an payment record for created sales basket
-		pb_Payment := constructPayments(pb_Basket.InvoiceNumber, eventTimestamp, pb_Basket.Total)
-		if err != nil {
-			os.Exit(1)
+		pb_Payments, payDelays := constructPayments(r, pb_Basket.InvoiceNumber, j.eventTime, nStoreId, money.Amount(pb_Basket.TotalMinor))
 
+		// and some of what was paid for comes back
+		var pb_Refund *types.Pb_Refund
+		var refundDelay time.Duration
+		if paid, paidDelay := paidFor(pb_Payments, payDelays); vGeneral.Refund_rate > 0 && paid > 0 && r.Float64() < vGeneral.Refund_rate {
+			pb_Refund, refundDelay = constructRefund(r, pb_Basket, j.eventTime, nStoreId, paid, paidDelay)
 		}
 
-		json_SalesBasket, err := json.Marshal(pb_Basket)
-		if err != nil {
-			grpcLog.Errorln(fmt.Sprintf("json.Marshal %s %s ", "pb_Basket", err))
-			os.Exit(1)
+		mRecordsGenerated.Inc()
+		mBasketValue.WithLabelValues(pb_Basket.Store.Name).Observe(pb_Basket.Total)
+		mBasketItems.WithLabelValues(pb_Basket.Store.Name).Observe(float64(len(pb_Basket.BasketItems)))
 
-		}
+		records <- record{seq: j.seq, start: j.start, basket: pb_Basket, payments: pb_Payments, refund: pb_Refund,
+			store: nStoreId, saleTime: j.eventTime, payDelays: payDelays, refundDelay: refundDelay}
+	}
+}
 
-		json_Payment, err := json.Marshal(pb_Payment)
-		if err != nil {
-			grpcLog.Errorln(fmt.Sprintf("json.Marshal %s %s", "pb_Payment", err))
-			os.Exit(1)
+// paidFor returns what the payments that went through, approved or partial, paid, and how long after the sale the
+// last of them was made, a refund can't be for more nor come before it
+func paidFor(pb_Payments []*types.Pb_Payment, payDelays []time.Duration) (paid money.Amount, last time.Duration) {
 
+	for i, pb_Payment := range pb_Payments {
+		if pb_Payment.PaidMinor > 0 {
+			paid += money.Amount(pb_Payment.PaidMinor)
+			if payDelays[i] > last {
+				last = payDelays[i]
+			}
 		}
+	}
+	return paid, last
+}
 
-		// echo to screen
-		if vGeneral.Debuglevel >= 2 {
-			prettyJSON(string(json_SalesBasket))
-			prettyJSON(string(json_Payment))
-		}
+// numberReceipt gives the record's basket the next invoice number of its terminal, as per Invoice_format, and its
+// payments and refund with it
+func numberReceipt(rec record) {
 
-		// Post to Confluent Kafka - if enabled
-		if vGeneral.KafkaEnabled == 1 {
+	local := rec.saleTime.Local()
+	if loc := vStores[rec.store].Location(); loc != nil {
+		local = rec.saleTime.In(loc)
+	}
 
-			if vGeneral.Debuglevel >= 2 {
-				grpcLog.Info("")
-				grpcLog.Info("Post to Confluent Kafka topics")
+	invoiceNumber := vReceipts.Next(rec.basket.Store.Id, rec.basket.TerminalPoint, local)
+
+	rec.basket.InvoiceNumber = invoiceNumber
+	for _, pb_Payment := range rec.payments {
+		pb_Payment.InvoiceNumber = invoiceNumber
+	}
+	if rec.refund != nil {
+		rec.refund.InvoiceNumber = invoiceNumber
+	}
+}
+
+// fanOut hands the record to every sink's writer
+func fanOut(outs []chan record, rec record) {
+
+	for _, out := range outs {
+		out <- rec
+	}
+}
+
+// sequence puts the records from the workers back into generation order, which keeps every store's baskets in order,
+// and fans them out to every sink's writer. When payments is not nil the payments, and refunds, are scheduled there for
+// when they happen instead of going out with their basket, when backfilling that is on the simulated clock, so here, just ahead
+// of the first basket sold after them. Returns the number of baskets sold, and passed on, once the records channel is closed.
+func sequence(records <-chan record, outs []chan record, payments *scheduler.Scheduler[record]) int {
+
+	emit := func(rec record) { fanOut(outs, rec) }
+
+	pending := make(map[int]record)
+	next := 0
+	sold := 0
+
+	for rec := range records {
+
+		pending[rec.seq] = rec
+
+		for {
+			rec, ok := pending[next]
+			if !ok {
+				break
 			}
+			delete(pending, next)
+			next++
 
-			// Sales Basket
-			offset, err := producer.ProduceMessage(&pb_Basket, vKafka.BasketTopicname, storeName)
-			if err != nil {
-				grpcLog.Errorln(fmt.Sprintf("producer.ProduceMessage %s %s", vKafka.BasketTopicname, err))
-				os.Exit(1)
+			// no store was open
+			if rec.basket == nil {
+				continue
 			}
-			fmt.Println("pb_Basket ", offset)
+			sold++
 
-			// Sales Payment
-			if vGeneral.Sleep > 0 {
-				n := rand.Intn(vGeneral.Sleep)
-				time.Sleep(time.Duration(n) * time.Millisecond)
+			// numbered here, in sale order, so every terminal's receipts follow on without gaps
+			if vReceipts != nil {
+				numberReceipt(rec)
 			}
 
-			offset, err = producer.ProduceMessage(&pb_Payment, vKafka.PaymentTopicname, storeName)
-			if err != nil {
-				grpcLog.Errorln(fmt.Sprintf("producer.ProduceMessage %s %s", vKafka.PaymentTopicname, err))
-				os.Exit(1)
+			if vGeneral.Debuglevel > 0 {
+				grpcLog.Infoln("")
+				grpcLog.Infoln("Record                        :", sold)
+
 			}
-			fmt.Println("pb_Payment ", offset)
 
-			vFlush++
+			// echo to screen
+			if vGeneral.Debuglevel >= 2 {
+
+				json_SalesBasket, err := json.Marshal(rec.basket)
+				if err != nil {
+					grpcLog.Errorln(fmt.Sprintf("json.Marshal %s %s ", "pb_Basket", err))
+
+				}
 
-			// Fush every flush_interval loops
-			if vFlush == vKafka.Flush_interval {
-				t := 10000
-				if r := producer.Flush(t); r > 0 {
-					grpcLog.Error(fmt.Sprintf("Failed to flush all messages after %d milliseconds. %d message(s) remain", t, r))
+				prettyJSON(string(json_SalesBasket))
 
-				} else {
-					if vGeneral.Debuglevel >= 1 {
-						grpcLog.Info(fmt.Sprintf("%s/%s, Messages flushed from the queue", count, vFlush))
+				for _, pb_Payment := range rec.payments {
+					json_Payment, err := json.Marshal(pb_Payment)
+					if err != nil {
+						grpcLog.Errorln(fmt.Sprintf("json.Marshal %s %s", "pb_Payment", err))
 
 					}
-					vFlush = 0
+					prettyJSON(string(json_Payment))
 				}
-			}
 
-			// Needs to move to our internal/kafka/producer.go
-
-			//
-			// We will decide if we want to keep this bit!!! or simplify it.
-			//
-			// Convenient way to Handle any events (back chatter) that we get
-			/* go func() {
-				doTerm := false
-				for !doTerm {
-					// The `select` blocks until one of the `case` conditions
-					// are met - therefore we run it in a Go Routine.
-					select {
-					case ev := <-producer.Events():
-						// Look at the type of Event we've received
-						switch ev.(type) {
-
-						case *kafka.Message:
-							// It's a delivery report
-							km := ev.(*kafka.Message)
-							if km.TopicPartition.Error != nil {
-								grpcLog.Error(fmt.Sprintf("☠️ Failed to send message to topic '%v'\tErr: %v",
-									string(*km.TopicPartition.Topic),
-									km.TopicPartition.Error))
-
-							} else {
-								if vGeneral.Debuglevel > 2 {
-									grpcLog.Info(fmt.Sprintf("✅ Message delivered to topic '%v'(partition %d at offset %d)",
-										string(*km.TopicPartition.Topic),
-										km.TopicPartition.Partition,
-										km.TopicPartition.Offset))
-
-								}
-							}
-
-						case kafka.Error:
-							// It's an error
-							em := ev.(kafka.Error)
-							grpcLog.Error(fmt.Sprint("☠️ Uh oh, caught an error:\n\t%v", em))
-
-						}
-					case <-termChan:
-						doTerm = true
+				if rec.refund != nil {
+					json_Refund, err := json.Marshal(rec.refund)
+					if err != nil {
+						grpcLog.Errorln(fmt.Sprintf("json.Marshal %s %s", "pb_Refund", err))
 
 					}
+					prettyJSON(string(json_Refund))
+				}
+			}
+
+			if payments != nil && vBackfill {
+				payments.Due(rec.saleTime, emit)
+				for i, pb_Payment := range rec.payments {
+					payments.Add(rec.saleTime.Add(rec.payDelays[i]), record{seq: rec.seq, start: time.Now(), payments: []*types.Pb_Payment{pb_Payment}, store: rec.store})
+				}
+				if rec.refund != nil {
+					payments.Add(rec.saleTime.Add(rec.refundDelay), record{seq: rec.seq, start: time.Now(), refund: rec.refund, store: rec.store})
+				}
+				rec.payments = nil
+				rec.refund = nil
+
+			} else if payments != nil {
+				// the basket goes out first, a payment due right away would otherwise race it to the sinks
+				pb_Payments, pb_Refund := rec.payments, rec.refund
+				rec.payments = nil
+				rec.refund = nil
+				emit(rec)
+
+				for i, pb_Payment := range pb_Payments {
+					// the payment's latency is measured from when it is due
+					due := time.Now().Add(time.Duration(float64(rec.payDelays[i]) / vGeneral.TimeCompression))
+					payments.Add(due, record{seq: rec.seq, start: due, payments: []*types.Pb_Payment{pb_Payment}, store: rec.store})
+				}
+				if pb_Refund != nil {
+					due := time.Now().Add(time.Duration(float64(rec.refundDelay) / vGeneral.TimeCompression))
+					payments.Add(due, record{seq: rec.seq, start: due, refund: pb_Refund, store: rec.store})
 				}
-				close(doneChan)
-			}() */
+				continue
+			}
 
+			emit(rec)
 		}
+	}
 
-		// Do we want to insertrecords/documents directly into Mongo Atlas?
-		if vGeneral.MongoAtlasEnabled == 1 {
-			msg_mongo_count += 1
+	// the payments made after the last sale of the backfill
+	if payments != nil && vBackfill {
+		payments.Drain(func(rec record) {
+			rec.start = time.Now()
+			emit(rec)
+		})
+	}
 
-			// Flush/insert
-			// Cast a byte string to BSon
-			// https://stackoverflow.com/questions/39785289/how-to-marshal-json-string-to-bson-document-for-writing-to-mongodb
-			// this way we don't need to care what the source structure is, it is all cast and inserted into the defined collection.
+	return sold
+}
 
-			// Sales Basket Doc
-			basketBytes, err := json.Marshal(pb_Basket)
-			if err != nil {
-				grpcLog.Error(fmt.Sprintf("Marchalling error: %s", err))
+// write posts every record it receives to the sink, until the channel is closed
+func write(s namedSink, in <-chan record) {
 
-			}
+	deferred, ok := s.Sink.(sink.Deferred)
+	if ok && !deferred.Defers() {
+		deferred = nil
+	}
 
-			basketdoc, err := JsonToBson(basketBytes)
-			if err != nil {
-				grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
+	for rec := range in {
 
-			}
+		var err error
+		if rec.basket != nil || len(rec.payments) > 0 {
+			err = s.Write(varSeed.Stores[rec.store].Name, rec.basket, rec.payments)
+		}
+		if err == nil && rec.refund != nil {
+			err = s.WriteRefund(rec.refund)
+		}
+
+		if err != nil {
+			grpcLog.Errorln(fmt.Sprintf("Writing to the %s sink failed: %s", s.name, err))
+
+		} else {
+			mRecordLatency.WithLabelValues(s.name).Observe(time.Since(rec.start).Seconds())
+
+		}
+
+		// a deferred sink's writes only count once committed / delivered
+		if deferred != nil {
+			settle(s.name, deferred)
+
+		} else if err != nil {
+			mSinkFailures.WithLabelValues(s.name).Inc()
+
+		} else {
+			mSinkWrites.WithLabelValues(s.name).Inc()
+
+		}
+
+		if vGeneral.Debuglevel > 1 {
+			grpcLog.Infoln(fmt.Sprintf("Total Time %-19s: %v Sec", s.name, time.Since(rec.start).Seconds()))
+
+		}
+	}
+}
+
+// settle counts the writes the deferred sink got through, or lost, since we last asked
+func settle(name string, deferred sink.Deferred) {
+
+	written, failed := deferred.Settled()
+	mSinkWrites.WithLabelValues(name).Add(float64(written))
+	mSinkFailures.WithLabelValues(name).Add(float64(failed))
+}
+
+// Serve /metrics on MetricsPort and/or push to PushgatewayURL every PushInterval seconds, the returned func
+// does a final push and stops the pusher.
+func startMetrics() func() {
 
-			// Payment Doc
-			paymentBytes, err := json.Marshal(pb_Payment)
-			if err != nil {
-				grpcLog.Error(fmt.Sprintf("Marchalling error: %s", err))
+	if vGeneral.MetricsPort > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+
+		go func() {
+			if err := http.ListenAndServe(fmt.Sprintf(":%d", vGeneral.MetricsPort), mux); err != nil {
+				grpcLog.Errorln("Metrics endpoint failed: ", err)
 
 			}
+		}()
+		grpcLog.Infoln(fmt.Sprintf("* Metrics served on           : http://%s:%d/metrics", vGeneral.Hostname, vGeneral.MetricsPort))
+
+	}
 
-			paymentdoc, err := JsonToBson(paymentBytes)
-			if err != nil {
-				grpcLog.Errorln("Oops, we had a problem JsonToBson converting the payload, ", err)
+	if vGeneral.PushgatewayURL == "" {
+		return func() {}
+	}
+
+	// Push PUTs, replacing what this job/instance pushed before
+	pusher := push.New(vGeneral.PushgatewayURL, "goproducer").Grouping("instance", vGeneral.Hostname).Gatherer(prometheus.DefaultGatherer)
+	pushMetrics := func() {
+		if err := pusher.Push(); err != nil {
+			grpcLog.Errorln("Metrics push failed: ", err)
+
+		}
+	}
 
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+
+		ticker := time.NewTicker(time.Duration(vGeneral.PushInterval) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				pushMetrics()
+			case <-done:
+				pushMetrics()
+				return
 			}
+		}
+	}()
 
-			// Single Record inserts
-			if vMongodb.Batch_size == 1 {
+	return func() {
+		close(done)
+		<-finished
+	}
+}
 
-				// Sales Basket
+// The simulated clock for a backfill from BackfillStart to BackfillEnd (default now), with its own random source
+func backfillClock() clock.Clock {
 
-				// Time to get this into the MondoDB Collection
-				result, err := basketcol.InsertOne(context.TODO(), basketdoc)
-				if err != nil {
-					grpcLog.Errorln("Oops, we had a problem inserting (I1) the document, ", err)
+	start, err := clock.ParseTime(vGeneral.BackfillStart, time.Local)
+	if err != nil {
+		grpcLog.Fatalln("Invalid BackfillStart: ", err)
 
-				}
+	}
 
-				if vGeneral.Debuglevel >= 2 {
-					// When you run this file, it should print:
-					// Document inserted with ID: ObjectID("...")
-					grpcLog.Infoln("Mongo Sales Basket Doc inserted with ID: ", result.InsertedID, "\n")
+	end := time.Now()
+	if vGeneral.BackfillEnd != "" {
+		end, err = clock.ParseTime(vGeneral.BackfillEnd, time.Local)
+		if err != nil {
+			grpcLog.Fatalln("Invalid BackfillEnd: ", err)
 
-				}
-				if vGeneral.Debuglevel >= 3 {
-					// prettyJSON takes a string which is actually JSON and makes it's pretty, and prints it.
-					prettyJSON(string(json_SalesBasket))
+		}
+	}
 
-				}
+	c, err := clock.NewSimulated(start.Local(), end.Local(), vGeneral.BackfillTps, rand.New(rand.NewSource(vRandom.Int63())))
+	if err != nil {
+		grpcLog.Fatalln("Backfill configuration error: ", err)
 
-				// Payment
+	}
 
-				// Time to get this into the MondoDB Collection
-				result, err = paymentcol.InsertOne(context.TODO(), paymentdoc)
-				if err != nil {
-					grpcLog.Errorln("Oops, we had a problem inserting (I1) the document, ", err)
+	if vShape != nil {
+		c.SetShape(vShape.Factor, vShape.Max())
+	}
 
-				}
+	grpcLog.Infoln(fmt.Sprintf("* Backfilling                 : %s -> %s at %.2f TPS", start.Local().Format(time.RFC3339), end.Local().Format(time.RFC3339), vGeneral.BackfillTps))
 
-				if vGeneral.Debuglevel >= 2 {
-					// When you run this file, it should print:
-					// Document inserted with ID: ObjectID("...")
-					grpcLog.Infoln("Mongo Payment Doc inserted with ID: ", result.InsertedID, "\n")
+	return c
+}
 
-				}
-				if vGeneral.Debuglevel >= 3 {
-					// prettyJSON takes a string which is actually JSON and makes it's pretty, and prints it.
-					prettyJSON(string(json_Payment))
+// Check the environment's seed file, all of it, and print what is wrong with it, false if it isn't fit for a run
+func validateSeed(arg string) bool {
 
-				}
+	vGeneral = loadConfig(arg)
+	varSeed = loadSeed(vGeneral.SeedFile)
 
-			} else {
+	report := seedcheck.Validate(varSeed, vGeneral)
 
-				basketdocs[msg_mongo_count-1] = basketdoc
-				paymentdocs[msg_mongo_count-1] = paymentdoc
+	grpcLog.Info("*")
+	grpcLog.Info("****** Seed File Validation *****")
+	grpcLog.Info("*")
+	grpcLog.Info("* Seed File is\t\t", vGeneral.SeedFile)
+	grpcLog.Info("* Stores are\t\t", len(varSeed.Stores))
+	grpcLog.Info("* Clerks are\t\t", len(varSeed.Clerks))
+	grpcLog.Info("* Products are\t\t", len(varSeed.Products))
+	grpcLog.Info("* Affinities are\t\t", len(varSeed.Affinities))
+	grpcLog.Info("* Promotions are\t\t", len(varSeed.Promotions))
+	grpcLog.Info("* Customers are\t\t", len(varSeed.Customers))
+	grpcLog.Info("*")
 
-				if msg_mongo_count%vMongodb.Batch_size == 0 {
+	// all Info, grpcLog's Error/Warning would repeat every line on stdout
+	for _, e := range report.Errors {
+		grpcLog.Info("* Error   : ", e)
+	}
+	for _, w := range report.Warnings {
+		grpcLog.Info("* Warning : ", w)
+	}
 
-					// Time to get this into the MondoDB Collection
+	grpcLog.Info("*")
+	grpcLog.Info(fmt.Sprintf("* %d errors, %d warnings", len(report.Errors), len(report.Warnings)))
+	if report.OK() {
+		grpcLog.Info("* Seed File is valid")
+	} else {
+		grpcLog.Info("* Seed File is NOT valid")
+	}
+	grpcLog.Info("*")
 
-					// Sales Basket
-					_, err = basketcol.InsertMany(context.TODO(), basketdocs)
-					if err != nil {
-						grpcLog.Errorln("Oops, we had a problem inserting (IM) the document, ", err)
+	return report.OK()
+}
 
-					}
-					if vGeneral.Debuglevel >= 2 {
-						grpcLog.Infoln("Mongo Sale Basket Docs inserted: ", msg_mongo_count)
+// Big worker... This is where all the magic is called from, ha ha.
+func runLoader(arg string) {
 
-					}
+	// Initialize the vGeneral struct variable - This holds our configuration settings.
+	vGeneral = loadConfig(arg)
+	buildDistributions()
 
-					// Sales Payment
-					_, err = paymentcol.InsertMany(context.TODO(), paymentdocs)
-					if err != nil {
-						grpcLog.Errorln("Oops, we had a problem inserting (IM) the document, ", err)
+	// Lets get Seed Data from the specified seed file
+	varSeed = loadSeed(vGeneral.SeedFile)
+	vStores = compileStores(varSeed)
+	vRoster = compileRoster(varSeed)
+	vCatalogs = buildCatalogs(varSeed)
+	vTenders = buildTenders(varSeed)
+	vTaxes = compileTaxes(varSeed)
+	vAffinities = compileAffinities(varSeed)
+	vPromotions = compilePromotions(varSeed)
+
+	// One seeded random source for the whole run
+	initRandom()
+
+	// {run} in the invoice numbers comes off the seed, so only now can we number them
+	if vGeneral.Invoice_format != "" {
+		var err error
+		vReceipts, err = receipt.New(vGeneral.Invoice_format, vGeneral.RandomSeed)
+		if err != nil {
+			grpcLog.Fatalln("Invalid Invoice_format: ", err)
 
-					}
-					if vGeneral.Debuglevel >= 2 {
-						grpcLog.Infoln("Mongo Payment Docs inserted: ", msg_mongo_count)
+		}
+		if !vReceipts.PerRun() {
+			grpcLog.Warningln("Invoice_format has no {run}, invoice numbers repeat those of earlier runs")
 
-					}
+		}
+	}
 
-					msg_mongo_count = 0
+	// gofakeit names them, so only now it's seeded
+	vCustomers = buildCustomers(varSeed)
 
-				}
+	// Quiet nights, busy lunchtimes and Saturdays...
+	if ts := vGeneral.TrafficShape; len(ts.Hours) > 0 || len(ts.Weekdays) > 0 || len(ts.Stores) > 0 {
+		storeIds := make([]string, len(varSeed.Stores))
+		for i, st := range varSeed.Stores {
+			storeIds[i] = st.Id
+		}
 
-			}
+		var err error
+		vShape, err = traffic.New(ts, storeIds)
+		if err != nil {
+			grpcLog.Fatalln("TrafficShape configuration error: ", err)
+
+		}
+	}
+
+	// Backfilling history runs on a simulated clock, as fast as we can, otherwise it's now
+	vClock = clock.Wall{}
+	if vGeneral.BackfillStart != "" {
+		vClock = backfillClock()
+		vBackfill = true
+		vGeneral.Sleep = 0
+	}
+
+	// Pace at a target rate / load profile if configured, this replaces the random sleeps, incl. the Kafka one
+	// between basket and payment
+	var vPacer *pacer.Pacer
+	if !vBackfill && (vGeneral.TargetTps > 0 || len(vGeneral.LoadProfile) > 0) {
+		var err error
+		vPacer, err = pacer.New(vGeneral.TargetTps, vGeneral.LoadProfile)
+		if err != nil {
+			grpcLog.Fatalln("Pacer configuration error: ", err)
 
 		}
+		if vShape != nil {
+			vPacer.SetShape(vShape.Factor)
+		}
+		vGeneral.Sleep = 0
+	}
 
-		// Save multiple Basket docs and Payment docs to a single basket file and single payment file for the run
-		if vGeneral.Json_to_file == 1 {
+	// Kafka, Mongo, json files... whatever we've been configured to write to
+	sinks := openSinks(arg)
 
-			if vGeneral.Debuglevel >= 2 {
-				grpcLog.Info("")
-				grpcLog.Info("JSON to File Flow")
+	stopMetrics := startMetrics()
 
-			}
+	// Ctrl-C / SIGTERM stops the generation, after which we still flush and close the sinks,
+	// a 2nd signal once we've stopped kills us the default way.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-			// Sales Basket
-			pretty_basket, err := json.MarshalIndent(pb_Basket, "", " ")
-			if err != nil {
-				grpcLog.Errorln("MarshalIndent error", err)
+	// if set to 0 then we want it to simply just run and run and run. so lets give it a pretty big number
+	if vGeneral.Testsize == 0 {
+		vGeneral.Testsize = 10000000000000
+	}
 
-			}
+	if vGeneral.Debuglevel > 0 {
+		grpcLog.Info("**** LETS GO Processing ****")
+		grpcLog.Infoln("")
 
-			if _, err = f_basket.WriteString(string(pretty_basket) + ",\n"); err != nil {
-				grpcLog.Errorln("os.WriteString error ", err)
+	}
+
+	// The pipeline: this loop hands out jobs => Workers x generate() => sequence() => a write() per sink
+	jobs := make(chan job, vGeneral.Workers*2)
+	records := make(chan record, vGeneral.Workers*2)
+
+	var wgWorkers sync.WaitGroup
+	for i := 0; i < vGeneral.Workers; i++ {
+		wgWorkers.Add(1)
+		go func() {
+			defer wgWorkers.Done()
+			generate(jobs, records)
+		}()
+	}
+	go func() {
+		wgWorkers.Wait()
+		close(records)
+	}()
+
+	var wgSinks sync.WaitGroup
+	outs := make([]chan record, len(sinks))
+	for i, s := range sinks {
+		outs[i] = make(chan record, vGeneral.Workers*2)
+		wgSinks.Add(1)
+		go func(s namedSink, in <-chan record) {
+			defer wgSinks.Done()
+			write(s, in)
+		}(s, outs[i])
+	}
+
+	// With DelayPayments the payments wait in the scheduler until they happen, on Ctrl-C whatever is still
+	// pending goes out right away so it isn't lost. When backfilling sequence() releases them on the simulated clock.
+	var payments *scheduler.Scheduler[record]
+	emitted := make(chan struct{})
+	if vGeneral.DelayPayments == 1 {
+		payments = scheduler.New[record]()
+	}
+	if payments != nil && !vBackfill {
+		go func() {
+			defer close(emitted)
+			emit := func(rec record) { fanOut(outs, rec) }
+
+			// on a signal whatever sequence() still schedules, until it closes payments, goes out right away as well
+			if n := payments.Serve(ctx, emit); n > 0 {
+				grpcLog.Infoln("Emitted pending payments      :", n)
 
 			}
+		}()
+
+	} else {
+		close(emitted)
+	}
+
+	processed := make(chan int, 1)
+	go func() {
+		n := sequence(records, outs, payments)
+		if payments != nil {
+			payments.Close()
+		}
+		processed <- n
+	}()
+
+	// this is to keep record of the total batch run time
+	vStart := time.Now()
+dispatch:
+	for count := 0; count < vGeneral.Testsize && ctx.Err() == nil; count++ {
 
-			// Sales Payment
-			pretty_pmnt, err := json.MarshalIndent(pb_Payment, "", " ")
-			if err != nil {
-				grpcLog.Errorln("MarshalIndent error", err)
+		if vPacer != nil {
+			changed, err := vPacer.Wait(ctx)
+			if err == pacer.ErrProfileDone {
+				grpcLog.Infoln("Load profile completed")
+				break
+
+			} else if err != nil {
+				break
 
 			}
 
-			if _, err = f_pmnt.WriteString(string(pretty_pmnt) + ",\n"); err != nil {
-				grpcLog.Errorln("os.WriteString error ", err)
+			if changed && vGeneral.Debuglevel > 0 {
+				grpcLog.Infoln(fmt.Sprintf("Load profile stage            :  %s, now at %.1f TPS", vPacer.Stage(), vPacer.Rate()))
 
 			}
+			mTargetTps.Set(vPacer.Rate())
+		}
+
+		eventTime, err := vClock.Next()
+		if err == clock.ErrEnd {
+			grpcLog.Infoln("Backfill completed            :", vGeneral.BackfillEnd)
+			break
 
 		}
 
-		if vGeneral.Debuglevel > 1 {
-			grpcLog.Infoln("Total Time                    :", time.Since(txnStart).Seconds(), "Sec")
+		// Every store is closed, or has nobody on shift, nothing's sold so it doesn't count. Live we wait for the first
+		// to trade instead of spinning through the night, backfilling the simulated clock gets there by itself.
+		if !trading(eventTime) {
+			count--
+			if !vBackfill {
+				open := nextOpen(eventTime)
+				if vGeneral.Debuglevel > 0 {
+					grpcLog.Infoln("No store trading, waiting     :", open.Format(time.RFC3339))
+
+				}
+				select {
+				case <-time.After(time.Until(open)):
+				case <-ctx.Done():
+				}
+			}
+			continue
+		}
 
+		// Sleep paced, or flat out, there is no rate to shape, so the traffic shape thins the events instead,
+		// dropping them in proportion to how far we are below the peak
+		if vShape != nil && vPacer == nil && !vBackfill && vRandom.Float64()*vShape.Max() >= vShape.Factor(eventTime) {
+			count-- // not sold, doesn't count
+
+			// nothing at all is sold this hour, wait for the first that sells instead of dropping events flat out
+			if resume := vShape.Resume(eventTime); resume.After(eventTime) {
+				select {
+				case <-time.After(time.Until(resume)):
+				case <-ctx.Done():
+				}
+			}
+
+		} else {
+			// We're going to time every record and push that to prometheus
+			select {
+			case jobs <- job{seq: count, seed: vRandom.Int63(), start: time.Now(), eventTime: eventTime}:
+			case <-ctx.Done():
+				break dispatch
+			}
 		}
 
 		// used to slow the data production/posting to kafka and safe to file system down.
 		if vGeneral.Sleep > 0 {
-			n := rand.Intn(vGeneral.Sleep) // if vGeneral.sleep = 1000, then n will be random value of 0 -> 1000  aka 0 and 1 second
+			n := vRandom.Intn(vGeneral.Sleep) // if vGeneral.sleep = 1000, then n will be random value of 0 -> 1000  aka 0 and 1 second
 			if vGeneral.Debuglevel >= 2 {
 				grpcLog.Infof("Going to sleep for            : %d Milliseconds\n", n)
 
 			}
-			time.Sleep(time.Duration(n) * time.Millisecond)
+			select {
+			case <-time.After(time.Duration(n) * time.Millisecond):
+			case <-ctx.Done():
+			}
 		}
 
 	}
 
+	if ctx.Err() != nil {
+		grpcLog.Infoln("")
+		grpcLog.Infoln("**** Interrupted, shutting down ****")
+
+	}
+
+	// let the jobs already handed out run through to the sinks
+	close(jobs)
+	vProcessed := <-processed
+
+	// and the delayed payments come due, a signal here cuts the wait short
+	if payments != nil && ctx.Err() == nil && payments.Len() > 0 {
+		grpcLog.Infoln("Waiting on delayed payments   :", payments.Len())
+
+	}
+	<-emitted
+	stop()
+
+	for _, out := range outs {
+		close(out)
+	}
+	closeSinks(sinks, &wgSinks)
+	stopMetrics()
+
 	grpcLog.Infoln("")
 	grpcLog.Infoln("**** DONE Processing ****")
 	grpcLog.Infoln("")
@@ -1197,8 +1828,8 @@ func runLoader(arg string) {
 	grpcLog.Infoln("Start                         : ", vStart)
 	grpcLog.Infoln("End                           : ", vEnd)
 	grpcLog.Infoln("Elapsed Time (Seconds)        : ", vElapse.Seconds())
-	grpcLog.Infoln("Records Processed             : ", vGeneral.Testsize)
-	grpcLog.Infoln(fmt.Sprintf("                              :  %.3f Txns/Second", float64(vGeneral.Testsize)/vElapse.Seconds()))
+	grpcLog.Infoln("Records Processed             : ", vProcessed)
+	grpcLog.Infoln(fmt.Sprintf("                              :  %.3f Txns/Second", float64(vProcessed)/vElapse.Seconds()))
 
 	grpcLog.Infoln("")
 
@@ -1212,7 +1843,31 @@ func main() {
 
 	arg = os.Args[1]
 
-	runLoader(arg)
+	// Optional flags following the environment, ie: go run cmd/main.go pb -seed 42
+	flags := flag.NewFlagSet(arg, flag.ExitOnError)
+	seed := flags.Int64("seed", 0, "random seed, overrides RandomSeed from the *_app.json file")
+	flags.Parse(os.Args[2:])
+
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			vSeedOverride = seed
+		}
+	})
+
+	// or a command, ie: go run cmd/main.go pb seed validate
+	switch command := strings.Join(flags.Args(), " "); command {
+	case "":
+		runLoader(arg)
+
+	case "seed validate":
+		if !validateSeed(arg) {
+			os.Exit(1)
+		}
+
+	default:
+		grpcLog.Fatalln(fmt.Sprintf("Unknown command %q, use: <env> [-seed <n>] [seed validate]", command))
+
+	}
 
 	grpcLog.Info("****** Completed          *****")
 
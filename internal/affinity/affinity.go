@@ -0,0 +1,116 @@
+package affinity
+
+import (
+	"fmt"
+	"math/rand"
+
+	"cmd/internal/catalog"
+	"cmd/types"
+)
+
+// Rule is a compiled TAffinity
+type Rule struct {
+	name         string
+	ifIds        map[string]bool
+	ifCategory   string
+	then         []string
+	thenCategory string
+	probability  float64
+}
+
+// Compile checks the seed's affinity rules against its products
+func Compile(seed types.TPSeed) ([]Rule, error) {
+
+	ids := make(map[string]bool, len(seed.Products))
+	categories := make(map[string]bool)
+	for _, p := range seed.Products {
+		ids[p.Id] = true
+		categories[p.Category] = true
+	}
+
+	rules := make([]Rule, 0, len(seed.Affinities))
+	for i, a := range seed.Affinities {
+
+		name := a.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+
+		if len(a.If) == 0 && a.IfCategory == "" {
+			return nil, fmt.Errorf("affinity %s: needs if or ifCategory", name)
+		}
+		if len(a.Then) == 0 && a.ThenCategory == "" {
+			return nil, fmt.Errorf("affinity %s: needs then or thenCategory", name)
+		}
+		if a.Probability < 0 || a.Probability > 1 {
+			return nil, fmt.Errorf("affinity %s: probability must be between 0 and 1", name)
+		}
+
+		for _, id := range append(append([]string(nil), a.If...), a.Then...) {
+			if !ids[id] {
+				return nil, fmt.Errorf("affinity %s: unknown product %s", name, id)
+			}
+		}
+		for _, c := range []string{a.IfCategory, a.ThenCategory} {
+			if c != "" && !categories[c] {
+				return nil, fmt.Errorf("affinity %s: unknown category %s", name, c)
+			}
+		}
+
+		rule := Rule{name: name, ifIds: make(map[string]bool), ifCategory: a.IfCategory, then: a.Then,
+			thenCategory: a.ThenCategory, probability: a.Probability}
+		for _, id := range a.If {
+			rule.ifIds[id] = true
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (rule Rule) matches(p types.TProductStruct) bool {
+	return rule.ifIds[p.Id] || (rule.ifCategory != "" && rule.ifCategory == p.Category)
+}
+
+// Apply returns the products the rules add to a basket holding anchors, from what the store sells (c).
+// Every rule fires at most once per basket, only on the anchors, added products don't set off further rules,
+// and nothing already in the basket is added again.
+func Apply(r *rand.Rand, rules []Rule, anchors []types.TProductStruct, c *catalog.Catalog) []types.TProductStruct {
+
+	inBasket := make(map[string]bool, len(anchors))
+	for _, p := range anchors {
+		inBasket[p.Id] = true
+	}
+
+	var added []types.TProductStruct
+	for _, rule := range rules {
+
+		matched := false
+		for _, p := range anchors {
+			if rule.matches(p) {
+				matched = true
+				break
+			}
+		}
+		if !matched || r.Float64() >= rule.probability {
+			continue
+		}
+
+		for _, id := range rule.then {
+			if p, ok := c.Product(id); ok && !inBasket[id] {
+				added = append(added, p)
+				inBasket[id] = true
+			}
+		}
+
+		if rule.thenCategory != "" {
+			if p, ok := c.PickCategory(r, rule.thenCategory); ok && !inBasket[p.Id] {
+				added = append(added, p)
+				inBasket[p.Id] = true
+			}
+		}
+	}
+
+	return added
+}
@@ -0,0 +1,173 @@
+package catalog
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"cmd/types"
+)
+
+// Catalog is what a store sells, at its prices, picked in proportion to each product's popularity
+type Catalog struct {
+	products   []types.TProductStruct
+	cumulative []float64 // running total of the weights, for a binary search pick
+	uniform    bool      // every product equally popular
+	byId       map[string]types.TProductStruct
+	byCategory map[string]*Catalog
+}
+
+func newCatalog() *Catalog {
+	return &Catalog{uniform: true, byId: make(map[string]types.TProductStruct), byCategory: make(map[string]*Catalog)}
+}
+
+func (c *Catalog) add(p types.TProductStruct, weight float64) {
+
+	if len(c.products) > 0 && weight != c.cumulative[0] {
+		c.uniform = false
+	}
+
+	total := weight
+	if n := len(c.cumulative); n > 0 {
+		total += c.cumulative[n-1]
+	}
+
+	c.products = append(c.products, p)
+	c.cumulative = append(c.cumulative, total)
+
+	if c.byId != nil {
+		c.byId[p.Id] = p
+
+		cat, ok := c.byCategory[p.Category]
+		if !ok {
+			cat = &Catalog{uniform: true} // no lookups needed within a category
+			c.byCategory[p.Category] = cat
+		}
+		cat.add(p, weight)
+	}
+}
+
+// Build a catalog per store, in the order of seed.Stores. A product's popularity is its weight, default 1,
+// overridden by the store's productWeights, a store with a products list only sells those and its prices
+// override the seed's, which are converted to the store's currency at its exchangeRate.
+func Build(seed types.TPSeed) ([]*Catalog, error) {
+
+	known := make(map[string]bool, len(seed.Products))
+	for _, p := range seed.Products {
+		if p.Weight < 0 {
+			return nil, fmt.Errorf("product %s (%s) has a negative weight", p.Id, p.Name)
+		}
+		known[p.Id] = true
+	}
+
+	catalogs := make([]*Catalog, len(seed.Stores))
+	for i, st := range seed.Stores {
+
+		for _, ids := range [][]string{st.Products, keys(st.ProductWeights), keys(st.Prices)} {
+			for _, id := range ids {
+				if !known[id] {
+					return nil, fmt.Errorf("store %s (%s) refers to unknown product %s", st.Id, st.Name, id)
+				}
+			}
+		}
+
+		if st.ExchangeRate < 0 {
+			return nil, fmt.Errorf("store %s (%s) has a negative exchange rate", st.Id, st.Name)
+		}
+
+		sells := make(map[string]bool, len(st.Products))
+		for _, id := range st.Products {
+			sells[id] = true
+		}
+
+		c := newCatalog()
+		for _, p := range seed.Products {
+
+			if len(sells) > 0 && !sells[p.Id] {
+				continue
+			}
+
+			weight := p.Weight
+			if weight == 0 {
+				weight = 1
+			}
+			if w, ok := st.ProductWeights[p.Id]; ok {
+				if w < 0 {
+					return nil, fmt.Errorf("store %s (%s) has a negative weight for product %s", st.Id, st.Name, p.Id)
+				}
+				weight = w
+			}
+			if weight == 0 {
+				continue
+			}
+
+			if price, ok := st.Prices[p.Id]; ok {
+				p.Price = price
+			} else if st.ExchangeRate > 0 {
+				p.Price = math.Round(p.Price*st.ExchangeRate*100) / 100
+			}
+
+			c.add(p, weight)
+		}
+
+		if len(c.products) == 0 {
+			return nil, fmt.Errorf("store %s (%s) has no products to sell", st.Id, st.Name)
+		}
+
+		catalogs[i] = c
+	}
+
+	return catalogs, nil
+}
+
+func keys(m map[string]float64) []string {
+
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+// Pick a product at random, the more popular the more likely
+func (c *Catalog) Pick(r *rand.Rand) types.TProductStruct {
+
+	if c.uniform {
+		return c.products[r.Intn(len(c.products))]
+	}
+
+	x := r.Float64() * c.cumulative[len(c.cumulative)-1]
+	i := sort.SearchFloat64s(c.cumulative, x)
+	if i < len(c.cumulative) && c.cumulative[i] == x {
+		i++ // x sits on a boundary, it belongs to the next product
+	}
+	if i >= len(c.products) {
+		i = len(c.products) - 1
+	}
+
+	return c.products[i]
+}
+
+// Product returns the product with the id as the store sells it, false if it doesn't
+func (c *Catalog) Product(id string) (types.TProductStruct, bool) {
+
+	p, ok := c.byId[id]
+	return p, ok
+}
+
+// PickCategory picks a product of the category at random, the more popular the more likely, false if the store
+// sells nothing in it
+func (c *Catalog) PickCategory(r *rand.Rand, category string) (types.TProductStruct, bool) {
+
+	cat, ok := c.byCategory[category]
+	if !ok {
+		return types.TProductStruct{}, false
+	}
+	return cat.Pick(r), true
+}
+
+// Len returns the number of products the store sells
+func (c *Catalog) Len() int {
+	return len(c.products)
+}
@@ -0,0 +1,94 @@
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// ErrEnd is returned by Next once the simulated clock has passed its end time
+var ErrEnd = errors.New("simulated clock reached its end")
+
+// Clock hands out the time of every next event (basket)
+type Clock interface {
+	Next() (time.Time, error)
+}
+
+// Wall is the real clock, every event happens now
+type Wall struct{}
+
+func (Wall) Next() (time.Time, error) {
+	return time.Now(), nil
+}
+
+// Simulated is a virtual clock running from start to end, moving forward per event by a random gap so events
+// arrive as a Poisson process at rate events per (simulated) second. It runs as fast as it is asked for the time.
+type Simulated struct {
+	now      time.Time
+	end      time.Time
+	rate     float64
+	r        *rand.Rand
+	shape    func(time.Time) float64
+	shapeMax float64
+}
+
+// NewSimulated returns a virtual clock, the first event happens a gap after start
+func NewSimulated(start time.Time, end time.Time, rate float64, r *rand.Rand) (*Simulated, error) {
+
+	if !end.After(start) {
+		return nil, fmt.Errorf("simulated clock end %s is not after start %s", end, start)
+	}
+	if rate <= 0 {
+		return nil, errors.New("simulated clock needs an arrival rate > 0")
+	}
+
+	return &Simulated{now: start, end: end, rate: rate, r: r}, nil
+}
+
+// SetShape multiplies the arrival rate by shape at the time of every event, max being the largest value shape returns
+func (c *Simulated) SetShape(shape func(time.Time) float64, max float64) {
+	c.shape = shape
+	c.shapeMax = max
+}
+
+// Next moves the clock on to the next event and returns its time, ErrEnd once that is past end
+func (c *Simulated) Next() (time.Time, error) {
+
+	rate := c.rate
+	if c.shape != nil {
+		rate *= c.shapeMax
+	}
+
+	for {
+		// exponentially distributed gaps with mean 1/rate seconds
+		gap := time.Duration(c.r.ExpFloat64() / rate * float64(time.Second))
+		c.now = c.now.Add(gap)
+
+		if c.now.After(c.end) {
+			return time.Time{}, ErrEnd
+		}
+
+		// thinning, arrivals at the peak rate are kept in proportion to the shape at their time
+		if c.shape == nil || c.r.Float64()*c.shapeMax < c.shape(c.now) {
+			return c.now, nil
+		}
+	}
+}
+
+// ParseTime accepts RFC3339, ie: 2026-09-01T08:00:00+02:00, or a plain date/date time taken in loc, ie: 2026-09-01
+// or 2026-09-01T08:00:00
+func ParseTime(value string, loc *time.Location) (time.Time, error) {
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("can't parse %q as a date/time, use 2006-01-02, 2006-01-02T15:04:05 or RFC3339", value)
+}
@@ -0,0 +1,168 @@
+package clock
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC) // a Monday
+
+func TestNewSimulated(t *testing.T) {
+
+	tests := []struct {
+		name string
+		end  time.Time
+		rate float64
+		ok   bool
+	}{
+		{"a day", start.AddDate(0, 0, 1), 1, true},
+		{"end before start", start.Add(-time.Hour), 1, false},
+		{"end at start", start, 1, false},
+		{"no rate", start.AddDate(0, 0, 1), 0, false},
+		{"negative rate", start.AddDate(0, 0, 1), -1, false},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewSimulated(start, tt.end, tt.rate, rand.New(rand.NewSource(1))); (err == nil) != tt.ok {
+			t.Errorf("%s: NewSimulated error = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestSimulated(t *testing.T) {
+
+	// hours 0 to 5 sell nothing, the rest at half or the full rate
+	shape := func(t time.Time) float64 {
+		switch {
+		case t.Hour() < 6:
+			return 0
+		case t.Hour() < 12:
+			return 0.5
+		}
+		return 1
+	}
+
+	tests := []struct {
+		name  string
+		end   time.Time
+		rate  float64 // per second
+		shape func(time.Time) float64
+	}{
+		{"an hour", start.Add(time.Hour), 1, nil},
+		{"a day, slow", start.AddDate(0, 0, 1), 0.05, nil},
+		{"a day, shaped", start.AddDate(0, 0, 1), 0.5, shape},
+	}
+
+	for _, tt := range tests {
+
+		c, err := NewSimulated(start, tt.end, tt.rate, rand.New(rand.NewSource(42)))
+		if err != nil {
+			t.Fatalf("%s: NewSimulated: %v", tt.name, err)
+		}
+		if tt.shape != nil {
+			c.SetShape(tt.shape, 1)
+		}
+
+		var events []time.Time
+		for {
+			ev, err := c.Next()
+			if err == ErrEnd {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: Next: %v", tt.name, err)
+			}
+			events = append(events, ev)
+		}
+
+		// never goes back, stays within [start, end], and once done stays done
+		prev := start
+		for i, ev := range events {
+			if ev.Before(prev) || ev.After(tt.end) {
+				t.Fatalf("%s: event %d at %s, after %s, outside %s - %s", tt.name, i, ev, prev, start, tt.end)
+			}
+			prev = ev
+		}
+		if _, err := c.Next(); err != ErrEnd {
+			t.Errorf("%s: Next after the end = %v, want ErrEnd", tt.name, err)
+		}
+
+		// the count of a Poisson process is within a few standard deviations (its square root) of the mean
+		want := tt.rate * tt.end.Sub(start).Seconds()
+		if tt.shape != nil {
+			want *= (6*0 + 6*0.5 + 12*1) / 24.0
+		}
+		if got := float64(len(events)); math.Abs(got-want) > 4*math.Sqrt(want) {
+			t.Errorf("%s: %d events, want about %.0f", tt.name, len(events), want)
+		}
+
+		if tt.shape != nil {
+			for _, ev := range events {
+				if tt.shape(ev) == 0 {
+					t.Fatalf("%s: event at %s, the shape is 0 then", tt.name, ev)
+				}
+			}
+		}
+	}
+}
+
+func TestSimulatedGaps(t *testing.T) {
+
+	// exponential gaps, mean and standard deviation both 1/rate
+	const rate, n = 2.0, 20000
+
+	c, err := NewSimulated(start, start.AddDate(1, 0, 0), rate, rand.New(rand.NewSource(7)))
+	if err != nil {
+		t.Fatalf("NewSimulated: %v", err)
+	}
+
+	prev := start
+	var sum, sumSq float64
+	for i := 0; i < n; i++ {
+		ev, err := c.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		gap := ev.Sub(prev).Seconds()
+		sum += gap
+		sumSq += gap * gap
+		prev = ev
+	}
+
+	mean := sum / n
+	sd := math.Sqrt(sumSq/n - mean*mean)
+	if math.Abs(mean-1/rate) > 0.02 || math.Abs(sd-1/rate) > 0.02 {
+		t.Errorf("gaps average %.3fs, standard deviation %.3fs, want both %.3fs", mean, sd, 1/rate)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+
+	loc := time.FixedZone("SAST", 2*60*60)
+
+	tests := []struct {
+		value string
+		want  time.Time
+		ok    bool
+	}{
+		{"2026-09-01T08:00:00+02:00", time.Date(2026, 9, 1, 6, 0, 0, 0, time.UTC), true},
+		{"2026-09-01", time.Date(2026, 9, 1, 0, 0, 0, 0, loc), true},
+		{"2026-09-01T08:00:00", time.Date(2026, 9, 1, 8, 0, 0, 0, loc), true},
+		{"2026-09-01 08:00:00", time.Date(2026, 9, 1, 8, 0, 0, 0, loc), true},
+		{"01/09/2026", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value, loc)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseTime(%q) error = %v, want ok %v", tt.value, err, tt.ok)
+			continue
+		}
+		if tt.ok && !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
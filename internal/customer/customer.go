@@ -0,0 +1,192 @@
+package customer
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"cmd/types"
+
+	"github.com/brianvoe/gofakeit"
+)
+
+// how much bigger, or smaller, than usual a segment's baskets are
+var segments = map[string]float64{"budget": 0.6, "regular": 1, "premium": 1.6}
+
+// Customer is a loyalty customer, the same one comes back visit after visit
+type Customer struct {
+	pb     *types.Customer
+	store  int     // home store, index into the seed's stores
+	visits float64 // relative visit frequency
+	spend  float64 // basket size multiplier, from the segment
+}
+
+// Proto returns the customer as stamped on the basket
+func (c *Customer) Proto() *types.Customer {
+	return c.pb
+}
+
+// Spend is how much bigger, or smaller, than usual the customer's baskets are
+func (c *Customer) Spend() float64 {
+	return c.spend
+}
+
+// group picks customers in proportion to how often they visit
+type group struct {
+	customers  []*Customer
+	cumulative []float64
+}
+
+func (g *group) add(c *Customer) {
+
+	total := c.visits
+	if n := len(g.cumulative); n > 0 {
+		total += g.cumulative[n-1]
+	}
+	g.customers = append(g.customers, c)
+	g.cumulative = append(g.cumulative, total)
+}
+
+func (g *group) pick(r *rand.Rand) *Customer {
+
+	x := r.Float64() * g.cumulative[len(g.cumulative)-1]
+	i := sort.SearchFloat64s(g.cumulative, x)
+	if i < len(g.cumulative) && g.cumulative[i] == x {
+		i++
+	}
+	if i >= len(g.customers) {
+		i = len(g.customers) - 1
+	}
+	return g.customers[i]
+}
+
+// Population is every loyalty customer of the run, all of them and by home store
+type Population struct {
+	all     group
+	byStore []group
+}
+
+// New returns the seed file's customers plus n generated ones, with gofakeit names, spread over the seed's stores.
+// Generated customers come from r and gofakeit's global source, so a seeded run has the same population every time,
+// nil when there are no customers at all.
+func New(seed types.TPSeed, n int, r *rand.Rand) (*Population, error) {
+
+	if len(seed.Stores) == 0 {
+		return nil, fmt.Errorf("customers need stores to shop at")
+	}
+
+	storeIndex := make(map[string]int, len(seed.Stores))
+	for i := len(seed.Stores) - 1; i >= 0; i-- {
+		storeIndex[seed.Stores[i].Id] = i // first store with the id wins
+	}
+
+	p := &Population{byStore: make([]group, len(seed.Stores))}
+	ids := make(map[string]bool, len(seed.Customers)+n)
+
+	for _, sc := range seed.Customers {
+
+		if sc.Id == "" {
+			return nil, fmt.Errorf("customer %q has no id", sc.Name)
+		}
+		if ids[sc.Id] {
+			return nil, fmt.Errorf("customer %s is in the seed file twice", sc.Id)
+		}
+		ids[sc.Id] = true
+
+		store := r.Intn(len(seed.Stores))
+		if sc.HomeStore != "" {
+			i, ok := storeIndex[sc.HomeStore]
+			if !ok {
+				return nil, fmt.Errorf("customer %s has unknown home store %s", sc.Id, sc.HomeStore)
+			}
+			store = i
+		}
+
+		segment := strings.ToLower(sc.Segment)
+		if segment == "" {
+			segment = "regular"
+		}
+		spend, ok := segments[segment]
+		if !ok {
+			return nil, fmt.Errorf("customer %s has unknown segment %q, use budget, regular or premium", sc.Id, sc.Segment)
+		}
+
+		visits := sc.Visits
+		if visits < 0 {
+			return nil, fmt.Errorf("customer %s has negative visits", sc.Id)
+		}
+		if visits == 0 {
+			visits = 1
+		}
+
+		loyaltyId := sc.LoyaltyId
+		if loyaltyId == "" {
+			loyaltyId = sc.Id
+		}
+
+		p.add(&Customer{
+			pb: &types.Customer{Id: sc.Id, LoyaltyId: loyaltyId, Name: sc.Name, Email: sc.Email,
+				HomeStore: seed.Stores[store].Id, Segment: segment},
+			store:  store,
+			visits: visits,
+			spend:  spend,
+		})
+	}
+
+	for i := 0; i < n; i++ {
+
+		id := fmt.Sprintf("C%08d", i+1)
+		for ids[id] {
+			id = "C" + id
+		}
+		ids[id] = true
+
+		segment := "regular"
+		switch x := r.Float64(); {
+		case x < 0.3:
+			segment = "budget"
+		case x >= 0.8:
+			segment = "premium"
+		}
+
+		store := r.Intn(len(seed.Stores))
+		first, last := gofakeit.FirstName(), gofakeit.LastName()
+
+		p.add(&Customer{
+			pb: &types.Customer{Id: id, LoyaltyId: fmt.Sprintf("L%010d", r.Int63n(1e10)), Name: first + " " + last,
+				Email: strings.ToLower(first + "." + last + "@" + gofakeit.DomainName()), HomeStore: seed.Stores[store].Id,
+				Segment: segment},
+			store: store,
+			// most shop now and again, a few all the time
+			visits: 0.1 + r.ExpFloat64(),
+			spend:  segments[segment],
+		})
+	}
+
+	if len(p.all.customers) == 0 {
+		return nil, nil
+	}
+
+	return p, nil
+}
+
+func (p *Population) add(c *Customer) {
+	p.all.add(c)
+	p.byStore[c.store].add(c)
+}
+
+// Pick a customer shopping at store, with homeShare a regular of the store, otherwise anyone, the more often
+// they visit the more likely
+func (p *Population) Pick(r *rand.Rand, store int, homeShare float64) *Customer {
+
+	if home := &p.byStore[store]; len(home.customers) > 0 && r.Float64() < homeShare {
+		return home.pick(r)
+	}
+	return p.all.pick(r)
+}
+
+// Len returns the number of customers
+func (p *Population) Len() int {
+	return len(p.all.customers)
+}
@@ -0,0 +1,188 @@
+package distribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	"cmd/types"
+)
+
+// how often we draw again for a value outside min..max before settling for the nearest bound
+const maxRetries = 100
+
+// Distribution draws whole numbers between min and max, both inclusive
+type Distribution interface {
+	Sample(r *rand.Rand) int
+}
+
+// New returns the distribution configured in cfg, bounded to min..max, an empty Type is uniform
+func New(cfg types.TDistribution, min int, max int) (Distribution, error) {
+
+	if max < min {
+		max = min
+	}
+
+	b := bounds{min: min, max: max}
+
+	switch strings.ToLower(cfg.Type) {
+	case "", "uniform":
+		return uniform{b}, nil
+
+	case "poisson":
+		if cfg.Mean <= 0 {
+			return nil, errors.New("poisson needs a Mean > 0")
+		}
+		return truncated{b, poisson(cfg.Mean)}, nil
+
+	case "normal":
+		if cfg.Stddev <= 0 {
+			return nil, errors.New("normal needs a Stddev > 0")
+		}
+		return truncated{b, func(r *rand.Rand) float64 { return r.NormFloat64()*cfg.Stddev + cfg.Mean }}, nil
+
+	case "lognormal":
+		if cfg.Sigma <= 0 {
+			return nil, errors.New("lognormal needs a Sigma > 0")
+		}
+		return truncated{b, func(r *rand.Rand) float64 { return math.Exp(r.NormFloat64()*cfg.Sigma + cfg.Mu) }}, nil
+
+	case "zipf":
+		if cfg.S <= 1 {
+			return nil, errors.New("zipf needs an S > 1")
+		}
+		v := cfg.V
+		if v < 1 {
+			v = 1
+		}
+		return zipf{b, cfg.S, v}, nil
+
+	case "empirical":
+		return newEmpirical(b, cfg.Values, cfg.Weights)
+	}
+
+	return nil, fmt.Errorf("unknown distribution %q, use uniform, poisson, normal, lognormal, zipf or empirical", cfg.Type)
+}
+
+type bounds struct {
+	min int
+	max int
+}
+
+func (b bounds) clamp(v int) int {
+
+	if v < b.min {
+		return b.min
+	}
+	if v > b.max {
+		return b.max
+	}
+	return v
+}
+
+// uniform draws every value between min and max equally often
+type uniform struct {
+	bounds
+}
+
+func (u uniform) Sample(r *rand.Rand) int {
+
+	if u.min >= u.max {
+		return u.min
+	}
+	return r.Intn(u.max-u.min+1) + u.min
+}
+
+// truncated draws from a continuous distribution, rounded, until it lands within the bounds
+type truncated struct {
+	bounds
+	draw func(r *rand.Rand) float64
+}
+
+func (t truncated) Sample(r *rand.Rand) int {
+
+	var v int
+	for i := 0; i < maxRetries; i++ {
+		v = int(math.Round(t.draw(r)))
+		if v >= t.min && v <= t.max {
+			return v
+		}
+	}
+	return t.clamp(v)
+}
+
+// poisson with the given mean, Knuth's method for small means, a normal approximation for large ones
+func poisson(mean float64) func(r *rand.Rand) float64 {
+
+	if mean > 30 {
+		return func(r *rand.Rand) float64 { return r.NormFloat64()*math.Sqrt(mean) + mean }
+	}
+
+	l := math.Exp(-mean)
+	return func(r *rand.Rand) float64 {
+		k := 0
+		for p := r.Float64(); p > l; p *= r.Float64() {
+			k++
+		}
+		return float64(k)
+	}
+}
+
+// zipf, min is the most likely value, each next one less so, the larger S the steeper
+type zipf struct {
+	bounds
+	s float64
+	v float64
+}
+
+func (z zipf) Sample(r *rand.Rand) int {
+
+	if z.min >= z.max {
+		return z.min
+	}
+	return z.min + int(rand.NewZipf(r, z.s, z.v, uint64(z.max-z.min)).Uint64())
+}
+
+// empirical picks from the given values in proportion to their weights, ie: a histogram of real POS data
+type empirical struct {
+	bounds
+	values     []int
+	cumulative []float64
+}
+
+func newEmpirical(b bounds, values []float64, weights []float64) (Distribution, error) {
+
+	if len(values) == 0 || len(values) != len(weights) {
+		return nil, errors.New("empirical needs Values and as many Weights")
+	}
+
+	e := empirical{bounds: b}
+	total := 0.0
+	for i, v := range values {
+		if weights[i] < 0 {
+			return nil, errors.New("empirical Weights must be >= 0")
+		}
+		total += weights[i]
+		e.values = append(e.values, b.clamp(int(math.Round(v))))
+		e.cumulative = append(e.cumulative, total)
+	}
+
+	if total <= 0 {
+		return nil, errors.New("empirical Weights add up to 0")
+	}
+
+	return e, nil
+}
+
+func (e empirical) Sample(r *rand.Rand) int {
+
+	x := r.Float64() * e.cumulative[len(e.cumulative)-1]
+	for i, c := range e.cumulative {
+		if x < c {
+			return e.values[i]
+		}
+	}
+	return e.values[len(e.values)-1]
+}
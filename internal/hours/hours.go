@@ -0,0 +1,177 @@
+package hours
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cmd/types"
+
+	// the zoneinfo database compiled in, so Timezone works wherever we run, ie: a scratch container
+	_ "time/tzdata"
+)
+
+// span is a trading period in time since midnight, close can go past 24h for stores trading past midnight
+type span struct {
+	open  time.Duration
+	close time.Duration
+}
+
+// Store is a seed store's trading hours, timezone and closed dates, ready to be asked if it is open
+type Store struct {
+	loc    *time.Location  // nil => no Timezone, local time
+	week   [7][]span       // by time.Weekday, all empty => no OpeningHours, always open
+	always bool            // no OpeningHours given
+	closed map[string]bool // 2006-01-02
+}
+
+var dayNames = map[string][]time.Weekday{
+	"sun":      {time.Sunday},
+	"mon":      {time.Monday},
+	"tue":      {time.Tuesday},
+	"wed":      {time.Wednesday},
+	"thu":      {time.Thursday},
+	"fri":      {time.Friday},
+	"sat":      {time.Saturday},
+	"daily":    {time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekend":  {time.Saturday, time.Sunday},
+}
+
+func parseDays(days []string) ([]time.Weekday, error) {
+
+	var out []time.Weekday
+	for _, d := range days {
+		key := strings.ToLower(strings.TrimSpace(d))
+		if len(key) > 3 && key != "daily" && key != "weekdays" && key != "weekend" {
+			key = key[:3] // monday => mon
+		}
+		w, ok := dayNames[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q, use mon..sun, daily, weekdays or weekend", d)
+		}
+		out = append(out, w...)
+	}
+	return out, nil
+}
+
+// parseClock turns 08:30 into 8h30m, 24:00 is allowed as a closing time
+func parseClock(value string) (time.Duration, error) {
+
+	var h, m int
+	if _, err := fmt.Sscanf(value, "%d:%d", &h, &m); err != nil || h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m > 0) {
+		return 0, fmt.Errorf("invalid time %q, use HH:MM", value)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// Compile the store's Timezone, OpeningHours and ClosedDates from the seed file
+func Compile(s types.TStoreStruct) (*Store, error) {
+
+	st := &Store{always: len(s.OpeningHours) == 0, closed: make(map[string]bool)}
+
+	if s.Timezone != "" {
+		loc, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("store %s (%s): %w", s.Id, s.Name, err)
+		}
+		st.loc = loc
+	}
+
+	week, err := compileWeek(s.OpeningHours)
+	if err != nil {
+		return nil, fmt.Errorf("store %s (%s): %w", s.Id, s.Name, err)
+	}
+	st.week = week
+
+	for _, d := range s.ClosedDates {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, fmt.Errorf("store %s (%s) closed date %q, use 2006-01-02", s.Id, s.Name, d)
+		}
+		st.closed[d] = true
+	}
+
+	return st, nil
+}
+
+// Shifts compiles a clerk's shifts, in loc, their store's timezone, into a Store that is open while they work,
+// no shifts means whenever the store is open
+func Shifts(shifts []types.TOpeningHours, loc *time.Location) (*Store, error) {
+
+	week, err := compileWeek(shifts)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{loc: loc, week: week, always: len(shifts) == 0, closed: make(map[string]bool)}, nil
+}
+
+// compileWeek turns the opening hours into spans by weekday
+func compileWeek(hours []types.TOpeningHours) (week [7][]span, err error) {
+
+	for _, oh := range hours {
+
+		days, err := parseDays(oh.Days)
+		if err != nil {
+			return week, err
+		}
+
+		opens, err := parseClock(oh.Open)
+		if err != nil {
+			return week, fmt.Errorf("open: %w", err)
+		}
+		closes, err := parseClock(oh.Close)
+		if err != nil {
+			return week, fmt.Errorf("close: %w", err)
+		}
+		if closes <= opens {
+			closes += 24 * time.Hour // trading past midnight
+		}
+
+		for _, d := range days {
+			week[d] = append(week[d], span{open: opens, close: closes})
+		}
+	}
+
+	return week, nil
+}
+
+// Location returns the store's timezone, nil if it doesn't have one
+func (s *Store) Location() *time.Location {
+	return s.loc
+}
+
+// Open tells if the store trades at t
+func (s *Store) Open(t time.Time) bool {
+
+	if s.loc != nil {
+		t = t.In(s.loc)
+	} else {
+		t = t.Local()
+	}
+
+	if s.closed[t.Format("2006-01-02")] {
+		return false
+	}
+
+	if s.always {
+		return true
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	since := t.Sub(midnight)
+
+	for _, sp := range s.week[t.Weekday()] {
+		if since >= sp.open && since < sp.close {
+			return true
+		}
+	}
+
+	// yesterday's hours running past midnight
+	for _, sp := range s.week[(t.Weekday()+6)%7] {
+		if since+24*time.Hour >= sp.open && since+24*time.Hour < sp.close {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,7 +1,10 @@
 package kafka
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/confluentinc/confluent-kafka-go/schemaregistry"
@@ -14,20 +17,56 @@ const (
 	nullOffset = -1
 )
 
+// ErrTransactionAborted is returned by CommitTransaction when the transaction could not be committed and was aborted instead
+var ErrTransactionAborted = errors.New("transaction aborted")
+
 // SRProducer interface
 type SRProducer interface {
 	ProduceMessage(msg proto.Message, topic string, key string) (int64, error)
+	ProduceAsync(msg proto.Message, topic string, key string, opaque interface{}) error
+	Stats() DeliveryStats
+	InitTransactions(ctx context.Context) error
+	BeginTransaction() error
+	CommitTransaction(ctx context.Context) error
+	AbortTransaction(ctx context.Context) error
 	Close()
 	Flush(t int) int
 }
 
+// Partition identifies a topic partition in the DeliveryStats offsets
+type Partition struct {
+	Topic     string
+	Partition int32
+}
+
+// Reports are called from the producer's delivery report goroutine, Delivered once for every message handed over
+// via ProduceAsync, with the opaque it was produced with and the error its delivery report carried, nil when it
+// made it, ClientError for the errors of the client itself, most of which it recovers from by itself
+type Reports struct {
+	Delivered   func(opaque interface{}, err error)
+	ClientError func(err error)
+}
+
+// DeliveryStats is a snapshot of the delivery reports seen so far for messages handed over via ProduceAsync
+type DeliveryStats struct {
+	Produced  int64               // handed to librdkafka
+	Delivered int64               // acknowledged by the broker
+	Failed    int64               // delivery report carried an error
+	Offsets   map[Partition]int64 // last acknowledged offset per topic partition
+}
+
 type srProducer struct {
 	producer   *kafka.Producer
 	serializer serde.Serializer
+
+	mu      sync.Mutex
+	stats   DeliveryStats
+	reports Reports
+	done    chan struct{}
 }
 
-// NewProducer returns kafka producer with schema registry
-func NewProducer(cm kafka.ConfigMap, srURL string) (SRProducer, error) {
+// NewProducer returns kafka producer with schema registry, reports get the outcome of the async messages
+func NewProducer(cm kafka.ConfigMap, srURL string, reports Reports) (SRProducer, error) {
 
 	p, err := kafka.NewProducer(&cm)
 	if err != nil {
@@ -36,21 +75,30 @@ func NewProducer(cm kafka.ConfigMap, srURL string) (SRProducer, error) {
 
 	c, err := schemaregistry.NewClient(schemaregistry.NewConfig(srURL))
 	if err != nil {
+		p.Close()
 		return nil, err
 	}
 
 	s, err := protobuf.NewSerializer(c, serde.ValueSerde, protobuf.NewSerializerConfig())
 	if err != nil {
+		p.Close()
 		return nil, err
 	}
 
-	return &srProducer{
+	sp := &srProducer{
 		producer:   p,
 		serializer: s,
-	}, nil
+		stats:      DeliveryStats{Offsets: make(map[Partition]int64)},
+		reports:    reports,
+		done:       make(chan struct{}),
+	}
+
+	go sp.deliveryReports()
+
+	return sp, nil
 }
 
-// ProduceMessage sends serialized message to kafka using schema registry
+// ProduceMessage sends serialized message to kafka using schema registry, waiting for its delivery report
 func (p *srProducer) ProduceMessage(msg proto.Message, topic string, key string) (int64, error) {
 
 	kafkaChan := make(chan kafka.Event)
@@ -76,23 +124,168 @@ func (p *srProducer) ProduceMessage(msg proto.Message, topic string, key string)
 	e := <-kafkaChan
 	switch ev := e.(type) {
 	case *kafka.Message:
+		if ev.TopicPartition.Error != nil {
+			return nullOffset, ev.TopicPartition.Error
+		}
 		return int64(ev.TopicPartition.Offset), nil
 
 	case kafka.Error:
-		return nullOffset, err
+		return nullOffset, ev
 
 	}
 
 	return nullOffset, nil
 }
 
+// ProduceAsync hands the serialized message to librdkafka and returns straight away, the delivery report is
+// picked up by deliveryReports() and passed to Reports.Delivered with opaque
+func (p *srProducer) ProduceAsync(msg proto.Message, topic string, key string, opaque interface{}) error {
+
+	payload, err := p.serializer.Serialize(topic, msg)
+	if err != nil {
+		return err
+	}
+
+	kmsg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic},
+		Value:          payload,
+		Key:            []byte(key),
+		Opaque:         opaque,
+	}
+
+	for {
+		err = p.producer.Produce(kmsg, nil)
+		if ke, ok := err.(kafka.Error); ok && ke.Code() == kafka.ErrQueueFull {
+			// librdkafka's local queue is full, give it a moment to drain and try again
+			p.producer.Flush(100)
+			continue
+		}
+		break
+	}
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.stats.Produced++
+	p.mu.Unlock()
+
+	return nil
+}
+
+// deliveryReports handles the events (back chatter) from librdkafka until the producer is closed
+func (p *srProducer) deliveryReports() {
+
+	defer close(p.done)
+
+	for e := range p.producer.Events() {
+		switch ev := e.(type) {
+		case *kafka.Message:
+			// It's a delivery report
+			p.mu.Lock()
+			if ev.TopicPartition.Error != nil {
+				p.stats.Failed++
+
+			} else {
+				p.stats.Delivered++
+				p.stats.Offsets[Partition{Topic: *ev.TopicPartition.Topic, Partition: ev.TopicPartition.Partition}] = int64(ev.TopicPartition.Offset)
+
+			}
+			p.mu.Unlock()
+
+			if p.reports.Delivered != nil {
+				var err error
+				if ev.TopicPartition.Error != nil {
+					err = fmt.Errorf("failed to deliver message to topic %s: %w", *ev.TopicPartition.Topic, ev.TopicPartition.Error)
+				}
+				p.reports.Delivered(ev.Opaque, err)
+			}
+
+		case kafka.Error:
+			// It's an error, the client recovers from most of these by itself
+			if p.reports.ClientError != nil {
+				p.reports.ClientError(ev)
+			}
+
+		}
+	}
+}
+
+// Stats returns a copy of the delivery counts and last offsets
+func (p *srProducer) Stats() DeliveryStats {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Offsets = make(map[Partition]int64, len(p.stats.Offsets))
+	for k, v := range p.stats.Offsets {
+		stats.Offsets[k] = v
+	}
+
+	return stats
+}
+
+// InitTransactions readies a producer configured with a transactional.id, call once before the first BeginTransaction
+func (p *srProducer) InitTransactions(ctx context.Context) error {
+	return p.producer.InitTransactions(ctx)
+}
+
+// BeginTransaction starts a transaction, everything produced until the commit/abort is part of it
+func (p *srProducer) BeginTransaction() error {
+	return p.producer.BeginTransaction()
+}
+
+// CommitTransaction flushes and commits the current transaction, retriable errors are retried until ctx expires,
+// if the transaction can't be committed anymore it is aborted and ErrTransactionAborted returned
+func (p *srProducer) CommitTransaction(ctx context.Context) error {
+
+	for {
+		err := p.producer.CommitTransaction(ctx)
+		if err == nil {
+			return nil
+		}
+
+		ke, ok := err.(kafka.Error)
+		if !ok {
+			return err
+		}
+
+		if ke.IsRetriable() && ctx.Err() == nil {
+			continue
+		}
+
+		if ke.TxnRequiresAbort() {
+			if errA := p.AbortTransaction(ctx); errA != nil {
+				return fmt.Errorf("abort after failed commit (%v) failed: %w", err, errA)
+			}
+			return fmt.Errorf("%w: %v", ErrTransactionAborted, err)
+		}
+
+		return err
+	}
+}
+
+// AbortTransaction aborts the current transaction, purging whatever was produced in it
+func (p *srProducer) AbortTransaction(ctx context.Context) error {
+
+	for {
+		err := p.producer.AbortTransaction(ctx)
+		if ke, ok := err.(kafka.Error); ok && ke.IsRetriable() && ctx.Err() == nil {
+			continue
+		}
+		return err
+	}
+}
+
 // Close schema registry and Kafka
 func (p *srProducer) Close() {
 	p.serializer.Close()
 	p.producer.Close()
+	<-p.done
 }
 
-// Close schema registry and Kafka
+// Flush waits up to t milliseconds for outstanding messages, returns the number still in the queue
 func (p *srProducer) Flush(t int) int {
 	return p.producer.Flush(t)
 
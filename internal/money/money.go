@@ -0,0 +1,42 @@
+package money
+
+import (
+	"fmt"
+	"math"
+)
+
+// Amount is money in minor units, cents, of a currency with 2 decimals. All the sums are done in it, so Total is
+// exactly Nett + Vat and the payments exactly add up to the Total, the float64s on the documents are derived from it.
+type Amount int64
+
+// FromFloat rounds f, ie: a seed file price, to the cent
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * 100))
+}
+
+// Float returns the amount as a float64, for the double fields of the documents
+func (a Amount) Float() float64 {
+	return float64(a) / 100
+}
+
+// Times returns the amount times n, ie: price x quantity
+func (a Amount) Times(n int) Amount {
+	return a * Amount(n)
+}
+
+// Mul returns the amount times f, rounded to the cent, half away from zero, ie: a tax or discount rate. Rates like
+// 0.14 aren't exact as a float64, so the product is first cut to 1/10000 of a cent, a half cent then stays a half cent.
+func (a Amount) Mul(f float64) Amount {
+	return Amount(math.Round(math.Round(float64(a)*f*1e4) / 1e4))
+}
+
+// String formats the amount as a decimal, ie: 1234 => 12.34, as used for Decimal128
+func (a Amount) String() string {
+
+	sign := ""
+	if a < 0 {
+		sign = "-"
+		a = -a
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, a/100, a%100)
+}
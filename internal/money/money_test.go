@@ -0,0 +1,81 @@
+package money
+
+import "testing"
+
+func TestFromFloat(t *testing.T) {
+
+	tests := []struct {
+		f    float64
+		want Amount
+	}{
+		{0, 0},
+		{0.01, 1},
+		{19.99, 1999},
+		{104.99, 10499},
+		{0.125, 13}, // half a cent, away from zero
+		{-5.5, -550},
+	}
+
+	for _, tt := range tests {
+		if got := FromFloat(tt.f); got != tt.want {
+			t.Errorf("FromFloat(%v) = %d, want %d", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+
+	tests := []struct {
+		name string
+		a    Amount
+		f    float64
+		want Amount
+	}{
+		{"exact", 10000, 0.14, 1400},
+		{"rounds up", 1999, 0.14, 280},                   // 279.86
+		{"rounds down", 7095, 0.14, 993},                 // 993.3
+		{"half cent up", 52495, 0.1, 5250},               // 5249.5
+		{"half cent float noise", 180, 0.175, 32},        // 31.5, 31.499999999999996 as a float64
+		{"inclusive rate noise", 1140, 0.14 / 1.14, 140}, // 140, 140.00000000000003 as a float64
+		{"negative half cent", -52495, 0.1, -5250},
+		{"one cent half", 1, 0.5, 1},
+		{"zero rate", 12345, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Mul(tt.f); got != tt.want {
+			t.Errorf("%s: %d.Mul(%v) = %d, want %d", tt.name, tt.a, tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestTimes(t *testing.T) {
+
+	if got := Amount(1999).Times(3); got != 5997 {
+		t.Errorf("1999.Times(3) = %d, want 5997", got)
+	}
+}
+
+func TestFloatAndString(t *testing.T) {
+
+	tests := []struct {
+		a     Amount
+		float float64
+		str   string
+	}{
+		{0, 0, "0.00"},
+		{5, 0.05, "0.05"},
+		{1234, 12.34, "12.34"},
+		{-5, -0.05, "-0.05"},
+		{-123456, -1234.56, "-1234.56"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Float(); got != tt.float {
+			t.Errorf("%d.Float() = %v, want %v", tt.a, got, tt.float)
+		}
+		if got := tt.a.String(); got != tt.str {
+			t.Errorf("%d.String() = %q, want %q", tt.a, got, tt.str)
+		}
+	}
+}
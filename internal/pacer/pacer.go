@@ -0,0 +1,171 @@
+package pacer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cmd/types"
+)
+
+// ErrProfileDone is returned by Wait once the last stage of the load profile has run its course
+var ErrProfileDone = errors.New("load profile completed")
+
+const (
+	// how long we wait before looking at the rate again when it is 0, ie: a ramp starting at 0 TPS
+	idlePoll = 100 * time.Millisecond
+
+	// how far behind schedule we allow ourselves to fall before giving up on catching up, this stops
+	// a slow sink from being followed by a burst of events
+	maxLag = time.Second
+)
+
+type stage struct {
+	name     string
+	duration time.Duration
+	startTps float64
+	endTps   float64
+}
+
+// Pacer spaces events to follow a target rate, either a flat TargetTps or a profile of stages each ramping linearly
+// from StartTps to EndTps over their Duration.
+type Pacer struct {
+	stages  []stage
+	flatTps float64
+	start   time.Time
+	next    time.Time
+	current int // index of the stage we're in, used to log stage changes
+	shape   func(time.Time) float64
+}
+
+// New returns a pacer for the flat targetTps, or for the profile if it has any stages, the clock starts on the first Wait
+func New(targetTps float64, profile []types.TLoadStage) (*Pacer, error) {
+
+	p := &Pacer{flatTps: targetTps, current: -1}
+
+	for i, s := range profile {
+
+		d, err := time.ParseDuration(s.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("load profile stage %d (%s): %w", i, s.Name, err)
+		}
+		if d <= 0 || s.StartTps < 0 || s.EndTps < 0 {
+			return nil, fmt.Errorf("load profile stage %d (%s): duration must be > 0 and rates >= 0", i, s.Name)
+		}
+
+		p.stages = append(p.stages, stage{name: s.Name, duration: d, startTps: s.StartTps, endTps: s.EndTps})
+	}
+
+	if len(p.stages) == 0 && targetTps <= 0 {
+		return nil, errors.New("pacer needs either a TargetTps > 0 or a LoadProfile")
+	}
+
+	return p, nil
+}
+
+// SetShape multiplies the rate by shape at the time of every event, ie: a traffic curve over the day
+func (p *Pacer) SetShape(shape func(time.Time) float64) {
+	p.shape = shape
+}
+
+// rateAt returns the target rate at elapsed into the run, and the index of the stage we're in, -1 when flat
+func (p *Pacer) rateAt(elapsed time.Duration) (float64, int, error) {
+
+	if len(p.stages) == 0 {
+		return p.flatTps, -1, nil
+	}
+
+	for i, s := range p.stages {
+		if elapsed < s.duration {
+			frac := float64(elapsed) / float64(s.duration)
+			return s.startTps + (s.endTps-s.startTps)*frac, i, nil
+		}
+		elapsed -= s.duration
+	}
+
+	return 0, len(p.stages), ErrProfileDone
+}
+
+// Rate returns the current target rate in events per second
+func (p *Pacer) Rate() float64 {
+
+	var r float64
+	if p.start.IsZero() {
+		r, _, _ = p.rateAt(0)
+	} else {
+		r, _, _ = p.rateAt(time.Since(p.start))
+	}
+
+	if p.shape != nil {
+		r *= p.shape(time.Now())
+	}
+	return r
+}
+
+// Stage returns the name of the current profile stage, empty when running a flat rate
+func (p *Pacer) Stage() string {
+
+	if p.current < 0 || p.current >= len(p.stages) {
+		return ""
+	}
+	return p.stages[p.current].name
+}
+
+// Wait blocks until the next event is due, returns ctx.Err() if cancelled and ErrProfileDone when the profile is finished.
+// The bool is true when we've just moved into a new profile stage.
+func (p *Pacer) Wait(ctx context.Context) (bool, error) {
+
+	now := time.Now()
+	if p.start.IsZero() {
+		p.start = now
+		p.next = now
+	}
+
+	changed := false
+	for {
+		rate, idx, err := p.rateAt(p.next.Sub(p.start))
+		if err != nil {
+			return false, err
+		}
+
+		if idx != p.current {
+			changed = true
+			p.current = idx
+		}
+
+		if p.shape != nil {
+			rate *= p.shape(p.next)
+		}
+
+		var wait time.Duration
+		if rate <= 0 {
+			// nothing due, look again in a moment
+			p.next = p.next.Add(idlePoll)
+			wait = time.Until(p.next)
+
+		} else {
+			wait = time.Until(p.next)
+		}
+
+		if wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return false, ctx.Err()
+			}
+		}
+
+		if rate <= 0 {
+			continue
+		}
+
+		// schedule the following event, if we've fallen too far behind don't try to catch up with a burst
+		p.next = p.next.Add(time.Duration(float64(time.Second) / rate))
+		if lag := time.Since(p.next); lag > maxLag {
+			p.next = time.Now()
+		}
+
+		return changed, nil
+	}
+}
@@ -0,0 +1,177 @@
+package pacer
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"cmd/types"
+)
+
+func TestNew(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		targetTps float64
+		profile   []types.TLoadStage
+		ok        bool
+	}{
+		{"flat", 10, nil, true},
+		{"profile", 0, []types.TLoadStage{{Name: "ramp", Duration: "1m", StartTps: 0, EndTps: 10}}, true},
+		{"no rate", 0, nil, false},
+		{"bad duration", 0, []types.TLoadStage{{Name: "ramp", Duration: "a minute", EndTps: 10}}, false},
+		{"zero duration", 0, []types.TLoadStage{{Name: "ramp", Duration: "0s", EndTps: 10}}, false},
+		{"negative rate", 0, []types.TLoadStage{{Name: "ramp", Duration: "1m", StartTps: -1, EndTps: 10}}, false},
+	}
+
+	for _, tt := range tests {
+		if _, err := New(tt.targetTps, tt.profile); (err == nil) != tt.ok {
+			t.Errorf("%s: New error = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestRateAt(t *testing.T) {
+
+	profile := []types.TLoadStage{
+		{Name: "warmup", Duration: "1m", StartTps: 0, EndTps: 100},
+		{Name: "steady", Duration: "2m", StartTps: 100, EndTps: 100},
+		{Name: "cooldown", Duration: "1m", StartTps: 100, EndTps: 20},
+	}
+	p, err := New(0, profile)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		elapsed time.Duration
+		rate    float64
+		stage   int
+		err     error
+	}{
+		{0, 0, 0, nil},
+		{15 * time.Second, 25, 0, nil}, // a quarter of the way up the ramp
+		{30 * time.Second, 50, 0, nil},
+		{time.Minute, 100, 1, nil},
+		{2 * time.Minute, 100, 1, nil},
+		{3 * time.Minute, 100, 2, nil},
+		{3*time.Minute + 30*time.Second, 60, 2, nil}, // halfway down
+		{4 * time.Minute, 0, 3, ErrProfileDone},
+		{time.Hour, 0, 3, ErrProfileDone},
+	}
+
+	for _, tt := range tests {
+		rate, stage, err := p.rateAt(tt.elapsed)
+		if math.Abs(rate-tt.rate) > 1e-9 || stage != tt.stage || err != tt.err {
+			t.Errorf("rateAt(%s) = %v, %d, %v, want %v, %d, %v", tt.elapsed, rate, stage, err, tt.rate, tt.stage, tt.err)
+		}
+	}
+
+	flat, err := New(42, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if rate, stage, err := flat.rateAt(time.Hour); rate != 42 || stage != -1 || err != nil {
+		t.Errorf("flat rateAt(1h) = %v, %d, %v, want 42, -1, nil", rate, stage, err)
+	}
+}
+
+func TestWaitStages(t *testing.T) {
+
+	p, err := New(0, []types.TLoadStage{
+		{Name: "one", Duration: "20ms", StartTps: 1000, EndTps: 1000},
+		{Name: "two", Duration: "20ms", StartTps: 1000, EndTps: 1000},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var stages []string
+	for {
+		changed, err := p.Wait(context.Background())
+		if err == ErrProfileDone {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+		if changed {
+			stages = append(stages, p.Stage())
+		}
+	}
+
+	if len(stages) != 2 || stages[0] != "one" || stages[1] != "two" {
+		t.Errorf("went through stages %v, want [one two]", stages)
+	}
+
+	// and stays done
+	if _, err := p.Wait(context.Background()); err != ErrProfileDone {
+		t.Errorf("Wait after the profile = %v, want ErrProfileDone", err)
+	}
+}
+
+func TestWaitCancelled(t *testing.T) {
+
+	p, err := New(0.1, nil) // an event every 10s
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := p.Wait(ctx); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+
+	cancel()
+	if _, err := p.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait = %v, want context.Canceled", err)
+	}
+}
+
+func TestWaitMaxLag(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		behind time.Duration // how far behind schedule the next event is
+		burst  bool          // whether the events we're behind on go out right away
+	}{
+		{"a little behind catches up", maxLag / 2, true},
+		{"too far behind starts over", 10 * maxLag, false},
+	}
+
+	for _, tt := range tests {
+
+		p, err := New(100, nil)
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+
+		// as if a slow sink held us up
+		now := time.Now()
+		p.start = now.Add(-time.Minute)
+		p.next = now.Add(-tt.behind)
+
+		if _, err := p.Wait(context.Background()); err != nil {
+			t.Fatalf("%s: Wait: %v", tt.name, err)
+		}
+
+		// catching up, the next event is still due in the past, otherwise it's paced from now on
+		if burst := p.next.Before(now); burst != tt.burst {
+			t.Errorf("%s: next event due %s from now, want a burst %v", tt.name, time.Until(p.next), tt.burst)
+		}
+	}
+}
+
+func TestShape(t *testing.T) {
+
+	p, err := New(100, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	p.SetShape(func(time.Time) float64 { return 0.5 })
+
+	if rate := p.Rate(); rate != 50 {
+		t.Errorf("Rate() = %v with a shape of 0.5, want 50", rate)
+	}
+}
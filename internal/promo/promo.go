@@ -0,0 +1,156 @@
+package promo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cmd/internal/money"
+	"cmd/types"
+)
+
+// Promotion is a compiled TPromotion
+type Promotion struct {
+	name     string
+	bxgy     bool
+	percent  float64
+	buy      int
+	get      int
+	products map[string]bool
+	category string
+	stores   map[string]bool
+	from     string // 2006-01-02, compared as strings
+	to       string
+}
+
+// Compile checks the seed's promotions against its products and stores
+func Compile(seed types.TPSeed) ([]Promotion, error) {
+
+	ids := make(map[string]bool, len(seed.Products))
+	categories := make(map[string]bool)
+	for _, p := range seed.Products {
+		ids[p.Id] = true
+		categories[p.Category] = true
+	}
+	stores := make(map[string]bool, len(seed.Stores))
+	for _, st := range seed.Stores {
+		stores[st.Id] = true
+	}
+
+	promos := make([]Promotion, 0, len(seed.Promotions))
+	for i, p := range seed.Promotions {
+
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+
+		promo := Promotion{name: name, category: p.Category, from: p.From, to: p.To,
+			products: make(map[string]bool), stores: make(map[string]bool)}
+
+		switch strings.ToLower(p.Type) {
+		case "percentage":
+			if p.Percent <= 0 || p.Percent > 100 {
+				return nil, fmt.Errorf("promotion %s: percent must be between 0 and 100", name)
+			}
+			promo.percent = p.Percent
+
+		case "bxgy":
+			if p.Buy < 1 || p.Get < 1 {
+				return nil, fmt.Errorf("promotion %s: bxgy needs buy and get >= 1", name)
+			}
+			promo.bxgy = true
+			promo.buy = p.Buy
+			promo.get = p.Get
+
+		default:
+			return nil, fmt.Errorf("promotion %s: unknown type %q, use percentage or bxgy", name, p.Type)
+		}
+
+		for _, id := range p.Products {
+			if !ids[id] {
+				return nil, fmt.Errorf("promotion %s: unknown product %s", name, id)
+			}
+			promo.products[id] = true
+		}
+		if p.Category != "" && !categories[p.Category] {
+			return nil, fmt.Errorf("promotion %s: unknown category %s", name, p.Category)
+		}
+		for _, id := range p.Stores {
+			if !stores[id] {
+				return nil, fmt.Errorf("promotion %s: unknown store %s", name, id)
+			}
+			promo.stores[id] = true
+		}
+		for _, d := range []string{p.From, p.To} {
+			if _, err := time.Parse("2006-01-02", d); d != "" && err != nil {
+				return nil, fmt.Errorf("promotion %s: date %q, use 2006-01-02", name, d)
+			}
+		}
+		if p.From != "" && p.To != "" && p.To < p.From {
+			return nil, fmt.Errorf("promotion %s: ends before it starts", name)
+		}
+
+		promos = append(promos, promo)
+	}
+
+	return promos, nil
+}
+
+// runs tells if the promotion is on at the store on day (2006-01-02)
+func (p Promotion) runs(storeId string, day string) bool {
+
+	if len(p.stores) > 0 && !p.stores[storeId] {
+		return false
+	}
+	return (p.from == "" || day >= p.from) && (p.to == "" || day <= p.to)
+}
+
+func (p Promotion) covers(item *types.BasketItem) bool {
+
+	if len(p.products) == 0 && p.category == "" {
+		return true
+	}
+	return p.products[item.Id] || (p.category != "" && p.category == item.Category)
+}
+
+// discount on the line, bxgy gives get of every buy + get units free
+func (p Promotion) discount(item *types.BasketItem) money.Amount {
+
+	price := money.Amount(item.PriceMinor)
+	if p.bxgy {
+		return price.Times(int(item.Quantity) / (p.buy + p.get) * p.get)
+	}
+	return price.Times(int(item.Quantity)).Mul(p.percent / 100)
+}
+
+// Apply the promotions running at the store at t, in the store's own time, to the items, each item gets the
+// best discount on offer, promotions don't stack. Returns the discount on all of them.
+func Apply(promos []Promotion, storeId string, t time.Time, items []*types.BasketItem) money.Amount {
+
+	day := t.Format("2006-01-02")
+
+	var running []Promotion
+	for _, p := range promos {
+		if p.runs(storeId, day) {
+			running = append(running, p)
+		}
+	}
+
+	var total money.Amount
+	for _, item := range items {
+		for _, p := range running {
+			if !p.covers(item) {
+				continue
+			}
+			if d := p.discount(item); d > money.Amount(item.DiscountMinor) {
+				item.DiscountMinor = int64(d)
+				item.Discount = d.Float()
+				item.Promotion = p.name
+			}
+		}
+		total += money.Amount(item.DiscountMinor)
+	}
+
+	return total
+}
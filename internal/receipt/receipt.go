@@ -0,0 +1,60 @@
+package receipt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Numbers hands out invoice numbers as per a format, with a sequence per store terminal that goes up by one per
+// sale, so a gap is a missing receipt. Not safe for concurrent use, number the sales where they are put in order.
+type Numbers struct {
+	format string
+	run    string           // identifies the run, the sequences start over with every run
+	seq    map[string]int64 // by store id + terminal
+}
+
+// New checks the format, ie: {store}-{terminal}-{run}-{date}-{seq}, it needs the {store}, {terminal} and {seq} that
+// make a number unique within a run. {run} is the run's random seed folded into 8 hex digits, without it the next run
+// hands out the same numbers again (a replay of a seed does anyway, as it does the same sales), {date} is the sale's
+// date in the store's timezone, 20261018
+func New(format string, seed int64) (*Numbers, error) {
+
+	for _, p := range []string{"{store}", "{terminal}", "{seq}"} {
+		if !strings.Contains(format, p) {
+			return nil, fmt.Errorf("invoice format %q needs %s, ie: {store}-{terminal}-{run}-{date}-{seq}", format, p)
+		}
+	}
+
+	rest := format
+	for _, p := range []string{"{store}", "{terminal}", "{run}", "{date}", "{seq}"} {
+		rest = strings.ReplaceAll(rest, p, "")
+	}
+	if strings.ContainsAny(rest, "{}") {
+		return nil, fmt.Errorf("invoice format %q, only {store}, {terminal}, {run}, {date} and {seq} can be used", format)
+	}
+
+	run := fmt.Sprintf("%08x", uint32(seed)^uint32(seed>>32))
+
+	return &Numbers{format: format, run: run, seq: make(map[string]int64)}, nil
+}
+
+// PerRun tells if the numbers carry {run}, if not every run numbers its sales from 1 again
+func (n *Numbers) PerRun() bool {
+	return strings.Contains(n.format, "{run}")
+}
+
+// Next returns the invoice number of the terminal's next sale, made at t, in the store's timezone
+func (n *Numbers) Next(storeId string, terminal string, t time.Time) string {
+
+	key := storeId + "/" + terminal
+	n.seq[key]++
+
+	return strings.NewReplacer(
+		"{store}", storeId,
+		"{terminal}", terminal,
+		"{run}", n.run,
+		"{date}", t.Format("20060102"),
+		"{seq}", fmt.Sprintf("%06d", n.seq[key]),
+	).Replace(n.format)
+}
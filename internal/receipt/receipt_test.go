@@ -0,0 +1,89 @@
+package receipt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewFormat(t *testing.T) {
+
+	tests := []struct {
+		format string
+		ok     bool
+		perRun bool
+	}{
+		{"{store}-{terminal}-{run}-{date}-{seq}", true, true},
+		{"{store}-{terminal}-{date}-{seq}", true, false},
+		{"{seq}/{terminal}/{store}", true, false},
+		{"{store}-{run}-{date}-{seq}", false, false},      // no {terminal}
+		{"{terminal}-{run}-{seq}", false, false},          // no {store}
+		{"{store}-{terminal}-{run}-{date}", false, false}, // no {seq}
+		{"{store}-{terminal}-{till}-{seq}", false, false},
+		{"{store}-{terminal}-{seq}}", false, false},
+		{"", false, false},
+	}
+
+	for _, tt := range tests {
+		n, err := New(tt.format, 42)
+		if (err == nil) != tt.ok {
+			t.Errorf("New(%q) error = %v, want ok %v", tt.format, err, tt.ok)
+			continue
+		}
+		if err == nil && n.PerRun() != tt.perRun {
+			t.Errorf("New(%q).PerRun() = %v, want %v", tt.format, n.PerRun(), tt.perRun)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+
+	n, err := New("{store}-{terminal}-{run}-{date}-{seq}", 42)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	day := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		store    string
+		terminal string
+		want     string
+	}{
+		{"324213413", "01", "324213413-01-0000002a-20261018-000001"},
+		{"324213413", "01", "324213413-01-0000002a-20261018-000002"},
+		{"324213413", "02", "324213413-02-0000002a-20261018-000001"}, // a sequence per terminal
+		{"324213412", "01", "324213412-01-0000002a-20261018-000001"}, // and per store
+		{"324213413", "01", "324213413-01-0000002a-20261018-000003"},
+	}
+
+	for i, tt := range tests {
+		if got := n.Next(tt.store, tt.terminal, day); got != tt.want {
+			t.Errorf("sale %d: Next(%s, %s) = %s, want %s", i, tt.store, tt.terminal, got, tt.want)
+		}
+	}
+}
+
+func TestSeqWidth(t *testing.T) {
+
+	n, err := New("{store}-{terminal}-{seq}", 1)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// {seq} is 6 digits, the numbers sort as they count up, until a terminal passes 999999
+	tests := []struct {
+		from int64 // where the terminal's sequence is at
+		want string
+	}{
+		{0, "S-T-000001"},
+		{9, "S-T-000010"},
+		{999998, "S-T-999999"},
+		{999999, "S-T-1000000"},
+	}
+
+	for _, tt := range tests {
+		n.seq["S/T"] = tt.from
+		if got := n.Next("S", "T", time.Time{}); got != tt.want {
+			t.Errorf("sale after %d numbered %s, want %s", tt.from, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package refund
+
+import (
+	"math/rand"
+
+	"cmd/internal/money"
+	"cmd/internal/tax"
+	"cmd/types"
+)
+
+var reasons = []string{"changed mind", "damaged", "faulty", "wrong size", "not as described", "duplicate purchase"}
+
+// Build a refund for some of the basket's items, at least one, each for between 1 and the quantity bought, at the
+// price paid, less its share of the item's discount, taxed as per the store's rules. A refund is never more than paid,
+// what was actually paid for the basket, units are left off until it fits, nil if not even one does. The caller fills
+// in the refund number, time and clerk.
+func Build(r *rand.Rand, basket *types.Pb_Basket, rules *tax.Rules, paid money.Amount) *types.Pb_Refund {
+
+	items := basket.BasketItems
+	n := 1 + r.Intn(len(items))
+
+	refund := &types.Pb_Refund{
+		InvoiceNumber: basket.InvoiceNumber,
+		Store:         basket.Store,
+		Reason:        reasons[r.Intn(len(reasons))],
+		Currency:      basket.Currency,
+	}
+
+	var bought []*types.BasketItem
+	var quantities []int32
+	for _, i := range r.Perm(len(items))[:n] {
+
+		quantity := int32(1)
+		if items[i].Quantity > 1 {
+			quantity += r.Int31n(items[i].Quantity)
+		}
+		bought = append(bought, items[i])
+		quantities = append(quantities, quantity)
+	}
+
+	for len(bought) > 0 {
+
+		refund.BasketItems = lines(bought, quantities)
+
+		nett, vat, taxes := rules.Apply(refund.BasketItems)
+		if nett+vat <= paid {
+			refund.Taxes = taxes
+			refund.NettMinor, refund.VatMinor, refund.TotalMinor = int64(nett), int64(vat), int64(nett+vat)
+			refund.Nett, refund.Vat, refund.Total = nett.Float(), vat.Float(), (nett + vat).Float()
+			return refund
+		}
+
+		// too much, one unit less of the last item
+		last := len(bought) - 1
+		if quantities[last]--; quantities[last] == 0 {
+			bought, quantities = bought[:last], quantities[:last]
+		}
+	}
+
+	return nil
+}
+
+// lines returns the refund's items, quantities[i] of items[i] each, with their share of the item's discount
+func lines(items []*types.BasketItem, quantities []int32) []*types.BasketItem {
+
+	out := make([]*types.BasketItem, len(items))
+	for i, item := range items {
+
+		discount := money.Amount(item.DiscountMinor).Mul(float64(quantities[i]) / float64(item.Quantity))
+
+		out[i] = &types.BasketItem{
+			Id:            item.Id,
+			Name:          item.Name,
+			Brand:         item.Brand,
+			Category:      item.Category,
+			Price:         item.Price,
+			Quantity:      quantities[i],
+			Discount:      discount.Float(),
+			Promotion:     item.Promotion,
+			PriceMinor:    item.PriceMinor,
+			DiscountMinor: int64(discount),
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,96 @@
+package roster
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"cmd/internal/hours"
+	"cmd/types"
+)
+
+type clerk struct {
+	id     *types.Idstruct
+	shifts *hours.Store // open while the clerk works
+}
+
+// Roster is who works at which store and when, by store id
+type Roster struct {
+	byStore map[string][]clerk
+}
+
+// Compile the seed's clerks into store rosters, stores is the compiled varSeed.Stores, same order, for their
+// timezones. Once one clerk has a store they all need one, and every store needs a clerk. Returns nil when no
+// clerk has a store, any clerk then serves at any store.
+func Compile(seed types.TPSeed, stores []*hours.Store) (*Roster, error) {
+
+	rostered := false
+	for _, c := range seed.Clerks {
+		if c.Store != "" {
+			rostered = true
+			break
+		}
+	}
+	if !rostered {
+		return nil, nil
+	}
+
+	locs := make(map[string]*time.Location, len(seed.Stores))
+	for i := len(seed.Stores) - 1; i >= 0; i-- {
+		locs[seed.Stores[i].Id] = stores[i].Location() // first store with the id wins
+	}
+
+	ro := &Roster{byStore: make(map[string][]clerk)}
+	for _, c := range seed.Clerks {
+
+		if c.Store == "" {
+			return nil, fmt.Errorf("clerk %s (%s) has no store, once one clerk has a store they all need one", c.Id, c.Name)
+		}
+		loc, ok := locs[c.Store]
+		if !ok {
+			return nil, fmt.Errorf("clerk %s (%s) has unknown store %s", c.Id, c.Name, c.Store)
+		}
+
+		shifts, err := hours.Shifts(c.Shifts, loc)
+		if err != nil {
+			return nil, fmt.Errorf("clerk %s (%s) shifts: %w", c.Id, c.Name, err)
+		}
+
+		ro.byStore[c.Store] = append(ro.byStore[c.Store], clerk{id: &types.Idstruct{Id: c.Id, Name: c.Name}, shifts: shifts})
+	}
+
+	for _, st := range seed.Stores {
+		if len(ro.byStore[st.Id]) == 0 {
+			return nil, fmt.Errorf("store %s (%s) has no clerks", st.Id, st.Name)
+		}
+	}
+
+	return ro, nil
+}
+
+// OnShift tells if any of the store's clerks is on shift at t
+func (ro *Roster) OnShift(storeId string, t time.Time) bool {
+
+	for _, c := range ro.byStore[storeId] {
+		if c.shifts.Open(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pick one of the store's clerks on shift at t, nil when nobody is, a store doesn't sell outside its clerks' shifts
+func (ro *Roster) Pick(r *rand.Rand, storeId string, t time.Time) *types.Idstruct {
+
+	var on []clerk
+	for _, c := range ro.byStore[storeId] {
+		if c.shifts.Open(t) {
+			on = append(on, c)
+		}
+	}
+	if len(on) == 0 {
+		return nil
+	}
+
+	return on[r.Intn(len(on))].id
+}
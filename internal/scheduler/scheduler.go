@@ -0,0 +1,193 @@
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Scheduler holds values until they are due, ie: payments until their simulated pay time, and hands them
+// out in due order.
+type Scheduler[T any] struct {
+	mu     sync.Mutex
+	items  items[T]
+	seq    uint64        // insertion order, keeps values with the same due time first in first out
+	closed bool          // nothing more will be added, Run returns once empty
+	wake   chan struct{} // nudges Run when something is added that may be due before what it is waiting on, or on Close
+	done   chan struct{} // closed by Close
+}
+
+type item[T any] struct {
+	due   time.Time
+	seq   uint64
+	value T
+}
+
+// items implements heap.Interface, earliest due first
+type items[T any] []item[T]
+
+func (h items[T]) Len() int { return len(h) }
+func (h items[T]) Less(i, j int) bool {
+	if h[i].due.Equal(h[j].due) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].due.Before(h[j].due)
+}
+func (h items[T]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *items[T]) Push(x any)   { *h = append(*h, x.(item[T])) }
+func (h *items[T]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// New returns an empty scheduler
+func New[T any]() *Scheduler[T] {
+	return &Scheduler[T]{wake: make(chan struct{}, 1), done: make(chan struct{})}
+}
+
+// Add schedules value to be handed out at due
+func (s *Scheduler[T]) Add(due time.Time, value T) {
+
+	s.mu.Lock()
+	heap.Push(&s.items, item[T]{due: due, seq: s.seq, value: value})
+	s.seq++
+	s.mu.Unlock()
+
+	s.nudge()
+}
+
+// Close tells Run nothing more will be added, so it can return once everything has been handed out
+func (s *Scheduler[T]) Close() {
+
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+	s.mu.Unlock()
+
+	s.nudge()
+}
+
+func (s *Scheduler[T]) nudge() {
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Len returns the number of values still waiting
+func (s *Scheduler[T]) Len() int {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.items.Len()
+}
+
+// pop returns the earliest value if it is due at now, otherwise when the earliest is due, zero if empty,
+// in which case closed tells if more may still come
+func (s *Scheduler[T]) pop(now time.Time) (value T, ok bool, next time.Time, closed bool) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.items.Len() == 0 {
+		return value, false, time.Time{}, s.closed
+	}
+
+	if s.items[0].due.After(now) {
+		return value, false, s.items[0].due, s.closed
+	}
+
+	return heap.Pop(&s.items).(item[T]).value, true, time.Time{}, s.closed
+}
+
+// Due hands every value due at or before now to emit, in due order, for callers running their own (virtual) clock
+func (s *Scheduler[T]) Due(now time.Time, emit func(T)) {
+
+	for {
+		v, ok, _, _ := s.pop(now)
+		if !ok {
+			return
+		}
+		emit(v)
+	}
+}
+
+// Drain hands every value still waiting to emit, in due order, whether due or not
+func (s *Scheduler[T]) Drain(emit func(T)) {
+
+	for {
+		s.mu.Lock()
+		if s.items.Len() == 0 {
+			s.mu.Unlock()
+			return
+		}
+		v := heap.Pop(&s.items).(item[T]).value
+		s.mu.Unlock()
+
+		emit(v)
+	}
+}
+
+// Run hands values to emit as they come due on the wall clock, until ctx is done or, once closed, nothing is waiting anymore.
+// Anything still waiting when ctx is done stays put, see Drain.
+func (s *Scheduler[T]) Run(ctx context.Context, emit func(T)) {
+
+	for {
+		v, ok, next, closed := s.pop(time.Now())
+		if ok {
+			emit(v)
+			continue
+		}
+
+		if next.IsZero() && closed {
+			return
+		}
+
+		var (
+			t     *time.Timer
+			timer <-chan time.Time
+		)
+		if !next.IsZero() {
+			t = time.NewTimer(time.Until(next))
+			timer = t.C
+		}
+
+		select {
+		case <-timer:
+		case <-s.wake:
+		case <-ctx.Done():
+		}
+
+		if t != nil {
+			t.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// Serve hands values to emit as they come due, like Run, and once ctx is done waits for Close, what is added in the
+// meantime can't be left behind, and hands out everything still waiting right away. Returns how many values went out
+// ahead of time that way.
+func (s *Scheduler[T]) Serve(ctx context.Context, emit func(T)) int {
+
+	s.Run(ctx, emit)
+	<-s.done
+
+	n := 0
+	s.Drain(func(v T) {
+		n++
+		emit(v)
+	})
+
+	return n
+}
@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collect gathers what a scheduler emits, Serve emits from its own goroutine
+type collect struct {
+	mu     sync.Mutex
+	values []int
+}
+
+func (c *collect) emit(v int) {
+	c.mu.Lock()
+	c.values = append(c.values, v)
+	c.mu.Unlock()
+}
+
+func TestServeShutdownWithPendingValues(t *testing.T) {
+
+	s := New[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// due long after the run is interrupted
+	later := time.Now().Add(time.Hour)
+	s.Add(later, 0)
+
+	var c collect
+	served := make(chan int)
+	go func() { served <- s.Serve(ctx, c.emit) }()
+
+	cancel()
+
+	// still being sequenced when the signal came, added after Run gave up
+	for i := 1; i <= 100; i++ {
+		s.Add(later.Add(time.Duration(i)*time.Second), i)
+	}
+
+	select {
+	case <-served:
+		t.Fatal("Serve returned before Close, values added after the signal would be lost")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.Close()
+	n := <-served
+
+	if n != 101 {
+		t.Errorf("Serve emitted %d pending values, want 101", n)
+	}
+	if len(c.values) != 101 {
+		t.Fatalf("emitted %d values, want 101", len(c.values))
+	}
+	for i, v := range c.values {
+		if v != i {
+			t.Fatalf("value %d emitted as number %d, want them in due order", v, i)
+		}
+	}
+	if s.Len() != 0 {
+		t.Errorf("%d values left behind", s.Len())
+	}
+}
+
+func TestServeWithoutSignal(t *testing.T) {
+
+	s := New[int]()
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		s.Add(now.Add(time.Duration(i)*time.Millisecond), i)
+	}
+	s.Close()
+
+	var c collect
+	if n := s.Serve(context.Background(), c.emit); n != 0 {
+		t.Errorf("Serve emitted %d values ahead of time, want 0", n)
+	}
+	if len(c.values) != 5 {
+		t.Errorf("emitted %d values, want 5", len(c.values))
+	}
+}
+
+func TestDueOrder(t *testing.T) {
+
+	start := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	tests := []struct {
+		name string
+		adds []int // due minute of values 0, 1, 2...
+		now  int
+		want []int
+	}{
+		{"in due order", []int{3, 1, 2}, 5, []int{1, 2, 0}},
+		{"same due time first in first out", []int{2, 1, 2, 1, 2}, 5, []int{1, 3, 0, 2, 4}},
+		{"only what is due", []int{1, 10, 2, 5}, 2, []int{0, 2}},
+		{"due at now", []int{2}, 2, []int{0}},
+		{"nothing due", []int{3, 4}, 2, nil},
+		{"empty", nil, 2, nil},
+	}
+
+	for _, tt := range tests {
+
+		s := New[int]()
+		for v, m := range tt.adds {
+			s.Add(at(m), v)
+		}
+
+		var c collect
+		s.Due(at(tt.now), c.emit)
+
+		if !equal(c.values, tt.want) {
+			t.Errorf("%s: Due emitted %v, want %v", tt.name, c.values, tt.want)
+		}
+		if left := len(tt.adds) - len(tt.want); s.Len() != left {
+			t.Errorf("%s: %d values left, want %d", tt.name, s.Len(), left)
+		}
+	}
+}
+
+func TestDrainAfterClose(t *testing.T) {
+
+	tests := []struct {
+		name string
+		adds []time.Duration // from now, values 0, 1, 2...
+		want []int
+	}{
+		{"not due yet, in due order", []time.Duration{time.Hour, time.Minute, 2 * time.Hour}, []int{1, 0, 2}},
+		{"due and not due", []time.Duration{time.Hour, -time.Minute}, []int{1, 0}},
+		{"empty", nil, nil},
+	}
+
+	for _, tt := range tests {
+
+		s := New[int]()
+		now := time.Now()
+		for v, d := range tt.adds {
+			s.Add(now.Add(d), v)
+		}
+		s.Close()
+
+		// Run gives up on the signal, whatever's still waiting is drained
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var c collect
+		s.Run(ctx, c.emit)
+		s.Drain(c.emit)
+
+		if !equal(c.values, tt.want) {
+			t.Errorf("%s: emitted %v, want %v", tt.name, c.values, tt.want)
+		}
+		if s.Len() != 0 {
+			t.Errorf("%s: %d values left behind", tt.name, s.Len())
+		}
+	}
+}
+
+func equal(a, b []int) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
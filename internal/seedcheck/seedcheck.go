@@ -0,0 +1,204 @@
+package seedcheck
+
+import (
+	"fmt"
+	"math/rand"
+
+	"cmd/internal/affinity"
+	"cmd/internal/catalog"
+	"cmd/internal/customer"
+	"cmd/internal/hours"
+	"cmd/internal/promo"
+	"cmd/internal/roster"
+	"cmd/internal/tax"
+	"cmd/internal/tender"
+	"cmd/internal/traffic"
+	"cmd/types"
+)
+
+// Report is what Validate found, errors make the seed file unfit for a run, warnings are worth a look
+type Report struct {
+	Errors   []string
+	Warnings []string
+}
+
+func (rep *Report) errorf(format string, a ...interface{}) {
+	rep.Errors = append(rep.Errors, fmt.Sprintf(format, a...))
+}
+
+func (rep *Report) warnf(format string, a ...interface{}) {
+	rep.Warnings = append(rep.Warnings, fmt.Sprintf(format, a...))
+}
+
+// OK tells if the seed file has no errors
+func (rep *Report) OK() bool {
+	return len(rep.Errors) == 0
+}
+
+// Validate checks the seed file, and the bits of *_app.json that refer to it, all of it rather than stopping at the
+// first problem: the required sections, unique ids, names, prices and everything one section says about another.
+func Validate(seed types.TPSeed, general types.Tp_general) *Report {
+
+	rep := &Report{}
+
+	if len(seed.Stores) == 0 {
+		rep.errorf("no Stores")
+	}
+	if len(seed.Clerks) == 0 {
+		rep.errorf("no Clerks")
+	}
+	if len(seed.Products) == 0 {
+		rep.errorf("no Products")
+	}
+
+	checkStores(rep, seed)
+	checkClerks(rep, seed)
+	checkProducts(rep, seed)
+
+	if general.Store < 0 || (general.Store > 0 && general.Store >= len(seed.Stores)) {
+		rep.errorf("Store %d in *_app.json is out of range, the seed file has %d stores, 0..%d", general.Store,
+			len(seed.Stores), len(seed.Stores)-1)
+	}
+
+	// from here on the references between the sections, as checked when a run starts up
+	if !rep.OK() {
+		rep.warnf("references between sections not checked, fix the errors above first")
+		return rep
+	}
+
+	stores := make([]*hours.Store, len(seed.Stores))
+	for i, st := range seed.Stores {
+
+		h, err := hours.Compile(st)
+		if err != nil {
+			rep.errorf("%s", err)
+			continue
+		}
+		stores[i] = h
+
+		weights := st.Tenders
+		if len(weights) == 0 {
+			weights = general.Tenders
+		}
+		if len(weights) > 0 {
+			if _, err := tender.NewMix(weights); err != nil {
+				rep.errorf("tenders of store %s (%s): %s", st.Id, st.Name, err)
+			}
+		}
+
+		if _, err := tax.Compile(st, seed, general.Vatrate, general.Currency); err != nil {
+			rep.errorf("%s", err)
+		}
+	}
+
+	if _, err := catalog.Build(seed); err != nil {
+		rep.errorf("%s", err)
+	}
+	if _, err := affinity.Compile(seed); err != nil {
+		rep.errorf("%s", err)
+	}
+	if _, err := promo.Compile(seed); err != nil {
+		rep.errorf("%s", err)
+	}
+	if _, err := customer.New(seed, 0, rand.New(rand.NewSource(1))); err != nil {
+		rep.errorf("%s", err)
+	}
+	if ts := general.TrafficShape; len(ts.Hours) > 0 || len(ts.Weekdays) > 0 || len(ts.Stores) > 0 {
+		storeIds := make([]string, len(seed.Stores))
+		for i, st := range seed.Stores {
+			storeIds[i] = st.Id
+		}
+		if _, err := traffic.New(ts, storeIds); err != nil {
+			rep.errorf("TrafficShape in *_app.json: %s", err)
+		}
+	}
+	if rep.OK() {
+		if _, err := roster.Compile(seed, stores); err != nil {
+			rep.errorf("%s", err)
+		}
+	}
+
+	return rep
+}
+
+func checkStores(rep *Report, seed types.TPSeed) {
+
+	ids := make(map[string]string)
+	names := make(map[string]string)
+	for i, st := range seed.Stores {
+
+		if st.Id == "" {
+			rep.errorf("store #%d (%s) has no id", i, st.Name)
+		} else if other, ok := ids[st.Id]; ok {
+			rep.errorf("store id %s is used by %s and %s", st.Id, other, st.Name)
+		} else {
+			ids[st.Id] = st.Name
+		}
+
+		if st.Name == "" {
+			rep.errorf("store %s has no name", st.Id)
+		} else if other, ok := names[st.Name]; ok {
+			rep.warnf("store name %s is used by %s and %s", st.Name, other, st.Id)
+		} else {
+			names[st.Name] = st.Id
+		}
+
+		for id, price := range st.Prices {
+			if price <= 0 {
+				rep.errorf("store %s (%s) price of product %s must be positive", st.Id, st.Name, id)
+			}
+		}
+
+		terminals := make(map[string]bool, len(st.Terminals))
+		for _, t := range st.Terminals {
+			if t == "" || terminals[t] {
+				rep.errorf("store %s (%s) terminal %q is empty or listed twice", st.Id, st.Name, t)
+			}
+			terminals[t] = true
+		}
+	}
+}
+
+func checkClerks(rep *Report, seed types.TPSeed) {
+
+	ids := make(map[string]string)
+	for i, c := range seed.Clerks {
+
+		if c.Id == "" {
+			rep.errorf("clerk #%d (%s) has no id", i, c.Name)
+		} else if other, ok := ids[c.Id]; ok {
+			rep.errorf("clerk id %s is used by %s and %s", c.Id, other, c.Name)
+		} else {
+			ids[c.Id] = c.Name
+		}
+
+		if c.Name == "" {
+			rep.errorf("clerk %s has no name", c.Id)
+		}
+	}
+}
+
+func checkProducts(rep *Report, seed types.TPSeed) {
+
+	ids := make(map[string]string)
+	for i, p := range seed.Products {
+
+		if p.Id == "" {
+			rep.errorf("product #%d (%s) has no id", i, p.Name)
+		} else if other, ok := ids[p.Id]; ok {
+			rep.errorf("product id %s is used by %s and %s", p.Id, other, p.Name)
+		} else {
+			ids[p.Id] = p.Name
+		}
+
+		if p.Name == "" {
+			rep.errorf("product %s has no name", p.Id)
+		}
+		if p.Price <= 0 {
+			rep.errorf("product %s (%s) price must be positive", p.Id, p.Name)
+		}
+		if p.Category == "" {
+			rep.warnf("product %s (%s) has no category, category promotions, affinities and tax rates can't match it", p.Id, p.Name)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package seedcheck
+
+import (
+	"strings"
+	"testing"
+
+	"cmd/types"
+)
+
+// valid is a small seed file that passes, every test breaks it in one place
+func valid() types.TPSeed {
+
+	return types.TPSeed{
+		Stores: []types.TStoreStruct{
+			{Id: "1", Name: "Rosebank"},
+			{Id: "2", Name: "Sandton", Products: []string{"100", "101"}},
+		},
+		Clerks: []types.TPClerkStruct{
+			{Id: "10", Name: "Martin", Store: "1"},
+			{Id: "11", Name: "Thabo", Store: "2"},
+		},
+		Products: []types.TProductStruct{
+			{Id: "100", Name: "Bread", Category: "Bakery", Price: 19.99},
+			{Id: "101", Name: "Milk", Category: "Dairy", Price: 24.99},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		breaks   func(seed *types.TPSeed, general *types.Tp_general)
+		ok       bool
+		errors   []string // each is part of one of the reported errors
+		warnings []string
+	}{
+		{
+			name:   "valid",
+			breaks: func(seed *types.TPSeed, general *types.Tp_general) {},
+			ok:     true,
+		},
+		{
+			name: "duplicate ids",
+			breaks: func(seed *types.TPSeed, general *types.Tp_general) {
+				seed.Stores[1].Id = "1"
+				seed.Clerks[1].Id = "10"
+				seed.Products[1].Id = "100"
+			},
+			errors: []string{
+				"store id 1 is used by Rosebank and Sandton",
+				"clerk id 10 is used by Martin and Thabo",
+				"product id 100 is used by Bread and Milk",
+			},
+			warnings: []string{"references between sections not checked"},
+		},
+		{
+			name: "duplicate store name",
+			breaks: func(seed *types.TPSeed, general *types.Tp_general) {
+				seed.Stores[1].Name = "Rosebank"
+			},
+			ok:       true,
+			warnings: []string{"store name Rosebank is used by 1 and 2"},
+		},
+		{
+			name: "missing section",
+			breaks: func(seed *types.TPSeed, general *types.Tp_general) {
+				seed.Clerks = nil
+			},
+			errors: []string{"no Clerks"},
+		},
+		{
+			name: "dangling reference",
+			breaks: func(seed *types.TPSeed, general *types.Tp_general) {
+				seed.Stores[1].Products = []string{"100", "999"}
+			},
+			errors: []string{"store 2 (Sandton) refers to unknown product 999"},
+		},
+		{
+			name: "clerk of an unknown store",
+			breaks: func(seed *types.TPSeed, general *types.Tp_general) {
+				seed.Clerks[1].Store = "3"
+			},
+			errors: []string{"clerk 11 (Thabo) has unknown store 3"},
+		},
+		{
+			name: "Store out of range",
+			breaks: func(seed *types.TPSeed, general *types.Tp_general) {
+				general.Store = 2
+			},
+			errors: []string{"Store 2 in *_app.json is out of range, the seed file has 2 stores, 0..1"},
+		},
+		{
+			name: "Store negative",
+			breaks: func(seed *types.TPSeed, general *types.Tp_general) {
+				general.Store = -1
+			},
+			errors: []string{"Store -1 in *_app.json is out of range"},
+		},
+	}
+
+	for _, tt := range tests {
+
+		seed := valid()
+		general := types.Tp_general{Vatrate: 0.15, Currency: "ZAR"}
+		tt.breaks(&seed, &general)
+
+		rep := Validate(seed, general)
+		if rep.OK() != tt.ok {
+			t.Errorf("%s: OK() = %v, want %v, errors %q", tt.name, rep.OK(), tt.ok, rep.Errors)
+		}
+		if len(rep.Errors) != len(tt.errors) {
+			t.Errorf("%s: %d errors %q, want %d", tt.name, len(rep.Errors), rep.Errors, len(tt.errors))
+		}
+		for _, want := range tt.errors {
+			if !reported(rep.Errors, want) {
+				t.Errorf("%s: errors %q, want one about %q", tt.name, rep.Errors, want)
+			}
+		}
+		for _, want := range tt.warnings {
+			if !reported(rep.Warnings, want) {
+				t.Errorf("%s: warnings %q, want one about %q", tt.name, rep.Warnings, want)
+			}
+		}
+	}
+}
+
+func reported(problems []string, want string) bool {
+
+	for _, p := range problems {
+		if strings.Contains(p, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package sink
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"cmd/types"
+
+	"github.com/google/uuid"
+)
+
+func init() {
+	Register("file", func() Sink { return &fileSink{} })
+}
+
+// fileSink saves all the basket docs to one file and all the payment docs to a second file for the run, refunds,
+// when there are any, go to a third
+type fileSink struct {
+	cfg      Config
+	runId    string
+	f_basket *os.File
+	f_pmnt   *os.File
+	f_refund *os.File
+}
+
+// Open a run file in Output_path, named <runId>_<suffix>.json
+func (s *fileSink) openFile(suffix string) (*os.File, error) {
+
+	loc := fmt.Sprintf("%s%s%s_%s.json", s.cfg.General.Output_path, string(os.PathSeparator), s.runId, suffix)
+	if s.cfg.General.Debuglevel > 2 {
+		s.cfg.Log.Infoln("Output File          :", loc)
+
+	}
+
+	f, err := os.OpenFile(loc, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile error %w", err)
+
+	}
+
+	return f, nil
+}
+
+func (s *fileSink) Open(cfg Config) (err error) {
+
+	s.cfg = cfg
+
+	// an empty Output_path would put the files in the root directory
+	if cfg.General.Output_path == "" {
+		return errors.New("Output_path is empty, set it to the directory the json files go in, ie: json_save")
+	}
+
+	// each time we run, and say we want to store the data created to disk, we create a pair of files for that run.
+	// this runId is used as the file name, prepended to either _basket.json, _pmnt.json or _refund.json
+	s.runId = uuid.New().String()
+
+	// Open file -> Baskets
+	if s.f_basket, err = s.openFile("basket"); err != nil {
+		return err
+	}
+
+	// Open file -> Payment
+	if s.f_pmnt, err = s.openFile("pmnt"); err != nil {
+		return err
+	}
+
+	// Open file -> Refund
+	if cfg.General.Refund_rate > 0 {
+		if s.f_refund, err = s.openFile("refund"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fileSink) writeDoc(f *os.File, v interface{}) error {
+
+	pretty, err := json.MarshalIndent(v, "", " ")
+	if err != nil {
+		return fmt.Errorf("MarshalIndent error %w", err)
+
+	}
+
+	if _, err = f.WriteString(string(pretty) + ",\n"); err != nil {
+		return fmt.Errorf("os.WriteString error %w", err)
+
+	}
+
+	return nil
+}
+
+func (s *fileSink) Write(store string, basket *types.Pb_Basket, payments []*types.Pb_Payment) error {
+
+	if s.cfg.General.Debuglevel >= 2 {
+		s.cfg.Log.Info("")
+		s.cfg.Log.Info("JSON to File Flow")
+
+	}
+
+	// Sales Basket
+	if basket != nil {
+		if err := s.writeDoc(s.f_basket, basket); err != nil {
+			return err
+		}
+	}
+
+	// Sales Payments
+	for _, payment := range payments {
+		if err := s.writeDoc(s.f_pmnt, payment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fileSink) WriteRefund(refund *types.Pb_Refund) error {
+
+	if s.f_refund == nil {
+		return fmt.Errorf("refund %s, but Refund_rate is 0", refund.RefundNumber)
+	}
+	return s.writeDoc(s.f_refund, refund)
+}
+
+func (s *fileSink) Flush() error {
+
+	for _, f := range []*os.File{s.f_basket, s.f_pmnt, s.f_refund} {
+		if f == nil {
+			continue
+		}
+		if err := f.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+
+	var err error
+	for _, f := range []*os.File{s.f_basket, s.f_pmnt, s.f_refund} {
+		if f == nil {
+			continue
+		}
+		if errC := f.Close(); errC != nil && err == nil {
+			err = errC
+		}
+	}
+
+	return err
+}
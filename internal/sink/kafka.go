@@ -0,0 +1,620 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"cmd/internal/kafka"
+	"cmd/types"
+
+	cpkafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/tkanos/gonfig"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("kafka", func() Sink { return &kafkaSink{} })
+}
+
+// kafkaSink posts the baskets, payments and refunds onto Confluent Kafka topics, serialized via the schema registry
+type kafkaSink struct {
+	cfg      Config
+	props    types.TKafka
+	producer kafka.SRProducer
+	vFlush   int  // We will use this to remember when we last flushed the kafka queues.
+	inTxn    bool // a transaction has been started and not yet committed/aborted
+
+	mu   sync.Mutex // done and lost, the delivery reports come in on the producer's goroutine
+	done int        // writes committed / delivered since Settled was last called
+	lost int        // and aborted / not delivered, or never making it into a transaction
+}
+
+// delivery tracks the messages of a record produced async, the record made it once every one of them is delivered
+type delivery struct {
+	name    string // for the log, ie: the invoice number
+	pending int
+	failed  bool
+}
+
+// how long we give librdkafka to init, commit or abort a transaction
+const txnTimeout = 60 * time.Second
+
+// Load Kafka specific configuration Parameters, this is so that we can gitignore this dev_kafka.json file/seperate
+// from the dev_app.json file
+func loadKafka(cfg Config) (types.TKafka, error) {
+
+	vKafka := types.TKafka{}
+
+	path, err := os.Getwd()
+	if err != nil {
+		return vKafka, fmt.Errorf("problem retrieving current path: %w", err)
+
+	}
+
+	fileName := fmt.Sprintf("%s/%s_kafka.json", path, cfg.Env)
+	err = gonfig.GetConf(fileName, &vKafka)
+	if err != nil {
+		return vKafka, fmt.Errorf("error reading Kafka file: %w", err)
+
+	}
+
+	cfg.General.KafkaConfigFile = fileName
+
+	if vKafka.RefundTopicname == "" {
+		vKafka.RefundTopicname = "p_salesrefunds"
+	}
+
+	if vKafka.Transactional == 1 && vKafka.TransactionalId == "" {
+		vKafka.TransactionalId = fmt.Sprintf("%s_%s", cfg.General.Hostname, vKafka.BasketTopicname)
+	}
+
+	if cfg.General.Debuglevel > 0 {
+
+		cfg.Log.Info("*")
+		cfg.Log.Info("* Kafka Config :")
+		cfg.Log.Info(fmt.Sprintf("* Current path : %s", path))
+		cfg.Log.Info(fmt.Sprintf("* Kafka File   : %s", fileName))
+		cfg.Log.Info("*")
+
+	}
+
+	vKafka.Sasl_password = os.Getenv("Sasl_password")
+	vKafka.Sasl_username = os.Getenv("Sasl_username")
+
+	if cfg.General.EchoConfig == 1 {
+		printKafkaConfig(cfg, vKafka)
+	}
+
+	return vKafka, nil
+}
+
+// print some more configurations
+func printKafkaConfig(cfg Config, vKafka types.TKafka) {
+
+	cfg.Log.Info("****** Kafka Connection Parameters *****")
+	cfg.Log.Info("*")
+	cfg.Log.Info("* Kafka bootstrap Server is\t", vKafka.Bootstrapservers)
+	cfg.Log.Info("* Kafka schema Registry is\t", vKafka.SchemaRegistryURL)
+	cfg.Log.Info("* Kafka Basket Topic is\t", vKafka.BasketTopicname)
+	cfg.Log.Info("* Kafka Payment Topic is\t", vKafka.PaymentTopicname)
+	cfg.Log.Info("* Kafka Refund Topic is\t", vKafka.RefundTopicname)
+	cfg.Log.Info("* Kafka # Parts is\t\t", vKafka.Numpartitions)
+	cfg.Log.Info("* Kafka Rep Factor is\t\t", vKafka.Replicationfactor)
+	cfg.Log.Info("* Kafka Retension is\t\t", vKafka.Retension)
+	cfg.Log.Info("* Kafka ParseDuration is\t", vKafka.Parseduration)
+
+	cfg.Log.Info("* Kafka SASL Mechanism is\t", vKafka.Sasl_mechanisms)
+	cfg.Log.Info("* Kafka SASL Username is\t", vKafka.Sasl_username)
+
+	cfg.Log.Info("*")
+	cfg.Log.Info("* Kafka Flush Size is\t\t", vKafka.Flush_interval)
+	cfg.Log.Info("* Kafka Async is\t\t", vKafka.Async)
+	cfg.Log.Info("* Kafka Transactional is\t", vKafka.Transactional)
+	if vKafka.Transactional == 1 {
+		cfg.Log.Info("* Kafka Transactional Id is\t", vKafka.TransactionalId)
+	}
+	cfg.Log.Info("*")
+	cfg.Log.Info("*******************************")
+
+	cfg.Log.Info("")
+
+}
+
+// Create Kafka topics if not exist, using admin client
+func createTopics(cfg Config, props types.TKafka, topics ...string) error {
+
+	// we using kafka aliased to cpkafka as we've created our own kafka class located in internal/kafka
+	cm := cpkafka.ConfigMap{
+		"bootstrap.servers":       props.Bootstrapservers,
+		"broker.version.fallback": "0.10.0.0",
+		"api.version.fallback.ms": 0,
+	}
+
+	if props.Sasl_mechanisms != "" {
+		cm["sasl.mechanisms"] = props.Sasl_mechanisms
+		cm["security.protocol"] = props.Security_protocol
+		cm["sasl.username"] = props.Sasl_username
+		cm["sasl.password"] = props.Sasl_password
+
+		if cfg.General.Debuglevel > 0 {
+			cfg.Log.Info("* Security Authentifaction configured in ConfigMap")
+
+		}
+	}
+
+	if cfg.General.Debuglevel > 0 {
+		cfg.Log.Info("* Basic Client ConfigMap compiled")
+	}
+
+	adminClient, err := cpkafka.NewAdminClient(&cm)
+	if err != nil {
+		return fmt.Errorf("admin client creation failed: %w", err)
+
+	}
+	defer adminClient.Close()
+
+	if cfg.General.Debuglevel > 0 {
+		cfg.Log.Info("* Admin Client Created Succeeded")
+
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	maxDuration, err := time.ParseDuration(props.Parseduration)
+	if err != nil {
+		return fmt.Errorf("error configuring maxDuration via ParseDuration: %s", props.Parseduration)
+
+	}
+
+	if cfg.General.Debuglevel > 0 {
+		cfg.Log.Info("* Configured maxDuration via ParseDuration")
+
+	}
+
+	for _, topic := range topics {
+
+		results, err := adminClient.CreateTopics(ctx,
+			[]cpkafka.TopicSpecification{{
+				Topic:             topic,
+				NumPartitions:     props.Numpartitions,
+				ReplicationFactor: props.Replicationfactor}},
+			cpkafka.SetAdminOperationTimeout(maxDuration))
+
+		if err != nil {
+			return fmt.Errorf("problem during the topic creation: %w", err)
+		}
+
+		// Check for specific topic errors
+		for _, result := range results {
+			if result.Error.Code() != cpkafka.ErrNoError &&
+				result.Error.Code() != cpkafka.ErrTopicAlreadyExists {
+				return fmt.Errorf("topic creation failed for %s: %v", result.Topic, result.Error.String())
+
+			}
+
+			if cfg.General.Debuglevel > 0 {
+				cfg.Log.Info(fmt.Sprintf("* Topic Creation Succeeded for %s", result.Topic))
+
+			}
+		}
+	}
+
+	cfg.Log.Info("")
+
+	return nil
+}
+
+func (s *kafkaSink) Open(cfg Config) (err error) {
+
+	s.cfg = cfg
+
+	// Initiale the props struct variable - This holds our Confluent Kafka configuration settings.
+	s.props, err = loadKafka(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Lets make sure the topic/s exist
+	topics := []string{s.props.BasketTopicname, s.props.PaymentTopicname}
+	if cfg.General.Refund_rate > 0 {
+		topics = append(topics, s.props.RefundTopicname)
+	}
+	if err = createTopics(cfg, s.props, topics...); err != nil {
+		return err
+	}
+
+	// --
+	// Create Producer instance
+	// https://docs.confluent.io/current/clients/confluent-kafka-go/index.html#NewProducer
+
+	if cfg.General.Debuglevel > 0 {
+		cfg.Log.Info("**** Configure Client Kafka Connection ****")
+		cfg.Log.Info("*")
+		cfg.Log.Info(fmt.Sprintf("* Kafka bootstrap Server is %s", s.props.Bootstrapservers))
+		if s.props.SchemaRegistryURL != "" {
+			cfg.Log.Info(fmt.Sprintf("* Schema Registry URL is    %s", s.props.SchemaRegistryURL))
+		}
+	}
+
+	cm := cpkafka.ConfigMap{
+		"bootstrap.servers":       s.props.Bootstrapservers,
+		"broker.version.fallback": "0.10.0.0",
+		"api.version.fallback.ms": 0,
+		"client.id":               cfg.General.Hostname,
+	}
+
+	if cfg.General.Debuglevel > 0 {
+		cfg.Log.Info("* Basic Client ConfigMap compiled")
+
+	}
+
+	if s.props.Sasl_mechanisms != "" {
+		cm["sasl.mechanisms"] = s.props.Sasl_mechanisms
+		cm["security.protocol"] = s.props.Security_protocol
+		cm["sasl.username"] = s.props.Sasl_username
+		cm["sasl.password"] = s.props.Sasl_password
+		if cfg.General.Debuglevel > 0 {
+			cfg.Log.Info("* Security Authentifaction configured in ConfigMap")
+
+		}
+	}
+
+	// exactly-once, a basket and its payment are committed together, or not at all
+	if s.props.Transactional == 1 {
+		cm["transactional.id"] = s.props.TransactionalId
+		if cfg.General.Debuglevel > 0 {
+			cfg.Log.Info("* Transactional Id configured in ConfigMap")
+
+		}
+	}
+
+	// internal/kafka/producer.go
+	s.producer, err = kafka.NewProducer(cm, s.props.SchemaRegistryURL, kafka.Reports{
+		Delivered: s.delivered,
+		ClientError: func(err error) {
+			// the client recovers from most of these by itself, they don't mean a message was lost
+			cfg.Log.Warningln("Kafka client error            : ", err)
+		},
+	})
+
+	// Check for errors in creating the Producer
+	if err != nil {
+		cfg.Log.Error(fmt.Sprintf("😢Oh noes, there's an error creating the Producer! %s", err))
+
+		if ke, ok := err.(cpkafka.Error); ok {
+			switch ec := ke.Code(); ec {
+			case cpkafka.ErrInvalidArg:
+				cfg.Log.Error(fmt.Sprintf("😢 Can't create the producer because you've configured it wrong (code: %d)!\n\t%v\n\nTo see the configuration options, refer to https://github.com/edenhill/librdkafka/blob/master/CONFIGURATION.md", ec, err))
+			default:
+				cfg.Log.Error(fmt.Sprintf("😢 Can't create the producer (Kafka error code %d)\n\tError: %v\n", ec, err))
+			}
+
+		} else {
+			// It's not a kafka.Error
+			cfg.Log.Error(fmt.Sprintf("😢 Oh noes, there's a generic error creating the Producer! %v", err.Error()))
+		}
+		return err
+
+	}
+
+	if s.props.Transactional == 1 {
+		ctx, cancel := context.WithTimeout(context.Background(), txnTimeout)
+		defer cancel()
+
+		if err = s.producer.InitTransactions(ctx); err != nil {
+			return fmt.Errorf("producer.InitTransactions %w", err)
+		}
+	}
+
+	if cfg.General.Debuglevel > 0 {
+		cfg.Log.Info("* Created Kafka Producer instance :")
+		cfg.Log.Info("")
+	}
+
+	return nil
+}
+
+// abort the open transaction after err, nothing produced since BeginTransaction will be seen by read_committed consumers.
+// If the abort fails the transaction stays open, Close tries again.
+func (s *kafkaSink) abort(err error) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), txnTimeout)
+	defer cancel()
+
+	s.count(0, s.vFlush)
+	s.vFlush = 0
+
+	if errA := s.producer.AbortTransaction(ctx); errA != nil {
+		return fmt.Errorf("%v, producer.AbortTransaction failed: %w", err, errA)
+	}
+	s.inTxn = false
+
+	return fmt.Errorf("transaction aborted: %w", err)
+}
+
+// commit the open transaction, the producer flushes outstanding messages as part of the commit. A commit that fails
+// without the producer aborting the transaction is aborted here, rather than left hanging until transaction.timeout.ms
+func (s *kafkaSink) commit() error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), txnTimeout)
+	defer cancel()
+
+	if err := s.producer.CommitTransaction(ctx); err != nil {
+		if errors.Is(err, kafka.ErrTransactionAborted) {
+			s.inTxn = false
+			s.count(0, s.vFlush)
+			s.vFlush = 0
+			return fmt.Errorf("producer.CommitTransaction %w", err)
+		}
+		return s.abort(fmt.Errorf("producer.CommitTransaction %w", err))
+	}
+	s.inTxn = false
+	s.count(s.vFlush, 0)
+
+	if s.cfg.General.Debuglevel >= 1 {
+		s.cfg.Log.Info(fmt.Sprintf("%d, Basket/Payment pairs committed", s.vFlush))
+
+	}
+	batchSize.WithLabelValues("kafka").Observe(float64(s.vFlush))
+	s.vFlush = 0
+
+	return nil
+}
+
+// produce the message, either waiting for its delivery report or, when Async is set, handing it to librdkafka,
+// its delivery report then goes to delivered() with d
+func (s *kafkaSink) produce(msg proto.Message, topic string, key string, name string, d *delivery) error {
+
+	if s.props.Async == 1 {
+		var opaque interface{}
+		if d != nil {
+			opaque = d
+		}
+		if err := s.producer.ProduceAsync(msg, topic, key, opaque); err != nil {
+			return fmt.Errorf("producer.ProduceAsync %s %w", topic, err)
+		}
+		kafkaProduced.WithLabelValues(topic).Inc()
+		return nil
+	}
+
+	offset, err := s.producer.ProduceMessage(msg, topic, key)
+	if err != nil {
+		return fmt.Errorf("producer.ProduceMessage %s %w", topic, err)
+	}
+	kafkaProduced.WithLabelValues(topic).Inc()
+	if s.cfg.General.Debuglevel >= 2 {
+		s.cfg.Log.Info(name, " ", offset)
+	}
+
+	return nil
+}
+
+// delivered takes the delivery report of an async message, the record it is part of counts once they're all in
+func (s *kafkaSink) delivered(opaque interface{}, err error) {
+
+	if err != nil {
+		kafkaDeliveryFailed.Inc()
+	}
+
+	d, ok := opaque.(*delivery)
+	if !ok {
+		// transactional, the commit says if it made it
+		if err != nil {
+			s.cfg.Log.Errorln(fmt.Sprintf("Kafka delivery failed: %s", err))
+		}
+		return
+	}
+
+	if err != nil && !d.failed {
+		s.cfg.Log.Errorln(fmt.Sprintf("Kafka delivery of %s failed: %s", d.name, err))
+	}
+	s.settle(d, 1, err != nil)
+}
+
+// settle n of the record's messages, once none are pending the record counts as done, or lost if any failed
+func (s *kafkaSink) settle(d *delivery, n int, failed bool) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d.pending -= n
+	d.failed = d.failed || failed
+	if d.pending > 0 {
+		return
+	}
+	if d.failed {
+		s.lost++
+	} else {
+		s.done++
+	}
+}
+
+// count writes done and lost
+func (s *kafkaSink) count(done int, lost int) {
+
+	s.mu.Lock()
+	s.done += done
+	s.lost += lost
+	s.mu.Unlock()
+}
+
+// track returns what follows the delivery of a record of n messages, nil unless that only becomes known from the
+// delivery reports, async and not in a transaction
+func (s *kafkaSink) track(name string, n int) *delivery {
+
+	if s.props.Async != 1 || s.props.Transactional == 1 {
+		return nil
+	}
+	return &delivery{name: name, pending: n}
+}
+
+// failed handles a message that couldn't be produced, the record and the rest of its messages are lost, when in a
+// transaction so is everything else in it
+func (s *kafkaSink) failed(d *delivery, unsent int, err error) error {
+
+	if s.inTxn {
+		s.count(0, 1)
+		return s.abort(err)
+	}
+	if d != nil {
+		s.settle(d, unsent, true)
+	}
+	return err
+}
+
+func (s *kafkaSink) Write(store string, basket *types.Pb_Basket, payments []*types.Pb_Payment) error {
+
+	if s.cfg.General.Debuglevel >= 2 {
+		s.cfg.Log.Info("")
+		s.cfg.Log.Info("Post to Confluent Kafka topics")
+	}
+
+	if s.props.Transactional == 1 && !s.inTxn {
+		if err := s.producer.BeginTransaction(); err != nil {
+			s.count(0, 1)
+			return fmt.Errorf("producer.BeginTransaction %w", err)
+		}
+		s.inTxn = true
+	}
+
+	n := len(payments)
+	name := ""
+	if len(payments) > 0 {
+		name = payments[0].InvoiceNumber
+	}
+	if basket != nil {
+		n++
+		name = basket.InvoiceNumber
+	}
+	d := s.track(name, n)
+
+	// Sales Basket
+	if basket != nil {
+		if err := s.produce(basket, s.props.BasketTopicname, store, "pb_Basket", d); err != nil {
+			return s.failed(d, n, err)
+		}
+		n--
+	}
+
+	// Sales Payments
+	for i, payment := range payments {
+
+		if i == 0 && basket != nil && s.cfg.General.Sleep > 0 {
+			n := s.cfg.Random.Intn(s.cfg.General.Sleep)
+			time.Sleep(time.Duration(n) * time.Millisecond)
+		}
+
+		// keyed by store, like the basket, so a store's payments stay on one partition, in order
+		if err := s.produce(payment, s.props.PaymentTopicname, store, "pb_Payment", d); err != nil {
+			return s.failed(d, n, err)
+		}
+		n--
+	}
+
+	return s.written()
+}
+
+func (s *kafkaSink) WriteRefund(refund *types.Pb_Refund) error {
+
+	if s.props.Transactional == 1 && !s.inTxn {
+		if err := s.producer.BeginTransaction(); err != nil {
+			s.count(0, 1)
+			return fmt.Errorf("producer.BeginTransaction %w", err)
+		}
+		s.inTxn = true
+	}
+
+	d := s.track(refund.RefundNumber, 1)
+	if err := s.produce(refund, s.props.RefundTopicname, refund.Store.Name, "pb_Refund", d); err != nil {
+		return s.failed(d, 1, err)
+	}
+
+	return s.written()
+}
+
+// Defers tells if the writes only count once their transaction commits or, when async, their delivery reports are in
+func (s *kafkaSink) Defers() bool {
+	return s.props.Transactional == 1 || s.props.Async == 1
+}
+
+// Settled returns, and resets, the writes committed / delivered and lost since the last call
+func (s *kafkaSink) Settled() (written, failed int) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	written, failed = s.done, s.lost
+	s.done, s.lost = 0, 0
+
+	return written, failed
+}
+
+// written counts a write towards the next flush
+func (s *kafkaSink) written() error {
+
+	s.vFlush++
+
+	// Fush every flush_interval loops, when transactional that is also where we commit, every pair if Flush_interval < 2
+	if s.vFlush == s.props.Flush_interval || (s.inTxn && s.props.Flush_interval < 2) {
+		return s.Flush()
+	}
+
+	return nil
+}
+
+func (s *kafkaSink) Flush() error {
+
+	if s.inTxn {
+		return s.commit()
+	}
+
+	t := 10000
+	if r := s.producer.Flush(t); r > 0 {
+		return fmt.Errorf("failed to flush all messages after %d milliseconds. %d message(s) remain", t, r)
+
+	}
+
+	if s.cfg.General.Debuglevel >= 1 {
+		s.cfg.Log.Info(fmt.Sprintf("%d, Messages flushed from the queue", s.vFlush))
+
+	}
+	if s.vFlush > 0 {
+		batchSize.WithLabelValues("kafka").Observe(float64(s.vFlush))
+	}
+	s.vFlush = 0
+
+	return nil
+}
+
+func (s *kafkaSink) Close() error {
+
+	if s.producer == nil {
+		return nil
+	}
+
+	err := s.Flush()
+	if err != nil && s.inTxn {
+		err = s.abort(err)
+	}
+
+	if s.props.Async == 1 {
+		stats := s.producer.Stats()
+		s.cfg.Log.Infoln("Kafka Messages Produced       : ", stats.Produced)
+		s.cfg.Log.Infoln("Kafka Messages Delivered      : ", stats.Delivered)
+		s.cfg.Log.Infoln("Kafka Messages Failed         : ", stats.Failed)
+		if s.cfg.General.Debuglevel > 0 {
+			for tp, offset := range stats.Offsets {
+				s.cfg.Log.Infoln(fmt.Sprintf("Kafka Last Offset             :  %s [%d] @ %d", tp.Topic, tp.Partition, offset))
+			}
+		}
+	}
+
+	s.producer.Close()
+
+	return err
+}
@@ -0,0 +1,178 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"cmd/internal/kafka"
+	"cmd/types"
+
+	glog "google.golang.org/grpc/grpclog"
+	"google.golang.org/protobuf/proto"
+)
+
+// asyncProducer keeps the opaque of every message handed to it, so the test can play its delivery reports back
+type asyncProducer struct {
+	kafka.SRProducer
+	opaques []interface{}
+	calls   int
+	failOn  int // ProduceAsync call that fails, 1 based, 0 none
+}
+
+func (p *asyncProducer) ProduceAsync(msg proto.Message, topic string, key string, opaque interface{}) error {
+
+	p.calls++
+	if p.calls == p.failOn {
+		return errors.New("queue full")
+	}
+	p.opaques = append(p.opaques, opaque)
+	return nil
+}
+
+func newAsyncSink(p *asyncProducer) *kafkaSink {
+
+	return &kafkaSink{
+		cfg: Config{
+			General: &types.Tp_general{},
+			Log:     glog.NewLoggerV2(io.Discard, io.Discard, io.Discard),
+		},
+		props:    types.TKafka{Async: 1, Flush_interval: 1000, BasketTopicname: "b", PaymentTopicname: "p"},
+		producer: p,
+	}
+}
+
+func sale(invoice string, payments int) (*types.Pb_Basket, []*types.Pb_Payment) {
+
+	basket := &types.Pb_Basket{InvoiceNumber: invoice}
+	var pmnts []*types.Pb_Payment
+	for i := 0; i < payments; i++ {
+		pmnts = append(pmnts, &types.Pb_Payment{InvoiceNumber: invoice})
+	}
+	return basket, pmnts
+}
+
+func TestAsyncWritesCountFromTheirOwnDeliveryReports(t *testing.T) {
+
+	p := &asyncProducer{}
+	s := newAsyncSink(p)
+
+	// a: basket + 2 payments, b: basket + 1 payment
+	for _, r := range []struct {
+		invoice  string
+		payments int
+	}{{"a", 2}, {"b", 1}} {
+		basket, payments := sale(r.invoice, r.payments)
+		if err := s.Write("store", basket, payments); err != nil {
+			t.Fatalf("Write %s: %v", r.invoice, err)
+		}
+	}
+
+	if written, failed := s.Settled(); written != 0 || failed != 0 {
+		t.Fatalf("settled %d written, %d failed before any delivery report, want 0, 0", written, failed)
+	}
+
+	// b's reports come in first, then a's with its first payment failing
+	failure := errors.New("msg timed out")
+	s.delivered(p.opaques[3], nil)
+	s.delivered(p.opaques[4], nil)
+	s.delivered(p.opaques[0], nil)
+	s.delivered(p.opaques[1], failure)
+
+	if written, failed := s.Settled(); written != 1 || failed != 0 {
+		t.Fatalf("settled %d written, %d failed with a still pending, want 1, 0", written, failed)
+	}
+
+	s.delivered(p.opaques[2], nil)
+
+	if written, failed := s.Settled(); written != 0 || failed != 1 {
+		t.Fatalf("settled %d written, %d failed, want a failed, 0, 1", written, failed)
+	}
+}
+
+func TestAsyncWriteFailingHalfway(t *testing.T) {
+
+	// the first payment can't be produced, the basket went out, the second payment never does
+	p := &asyncProducer{failOn: 2}
+	s := newAsyncSink(p)
+
+	basket, payments := sale("a", 2)
+	if err := s.Write("store", basket, payments); err == nil {
+		t.Fatal("Write succeeded, want the produce error")
+	}
+	if written, failed := s.Settled(); written != 0 || failed != 0 {
+		t.Fatalf("settled %d written, %d failed with the basket pending, want 0, 0", written, failed)
+	}
+
+	s.delivered(p.opaques[0], nil)
+
+	if written, failed := s.Settled(); written != 0 || failed != 1 {
+		t.Fatalf("settled %d written, %d failed, want 0, 1", written, failed)
+	}
+}
+
+// txnProducer fails its commits with commitErr and counts the aborts
+type txnProducer struct {
+	kafka.SRProducer
+	commitErr error
+	aborts    int
+}
+
+func (p *txnProducer) BeginTransaction() error { return nil }
+func (p *txnProducer) ProduceMessage(msg proto.Message, topic string, key string) (int64, error) {
+	return 0, nil
+}
+func (p *txnProducer) CommitTransaction(ctx context.Context) error { return p.commitErr }
+func (p *txnProducer) AbortTransaction(ctx context.Context) error {
+	p.aborts++
+	return nil
+}
+func (p *txnProducer) Close() {}
+
+func TestFailedCommitIsAborted(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		commitErr error
+		aborts    int
+	}{
+		{"commit fails, still open", errors.New("coordinator not available"), 1},
+		{"producer aborted it already", fmt.Errorf("%w: fenced", kafka.ErrTransactionAborted), 0},
+		{"committed", nil, 0},
+	}
+
+	for _, tt := range tests {
+
+		p := &txnProducer{commitErr: tt.commitErr}
+		s := &kafkaSink{
+			cfg:      Config{General: &types.Tp_general{}, Log: glog.NewLoggerV2(io.Discard, io.Discard, io.Discard)},
+			props:    types.TKafka{Transactional: 1, Flush_interval: 1000, BasketTopicname: "b", PaymentTopicname: "p"},
+			producer: p,
+		}
+
+		for _, invoice := range []string{"a", "b"} {
+			basket, payments := sale(invoice, 1)
+			if err := s.Write("store", basket, payments); err != nil {
+				t.Fatalf("%s: Write %s: %v", tt.name, invoice, err)
+			}
+		}
+
+		err := s.Close()
+		if (err != nil) != (tt.commitErr != nil) {
+			t.Errorf("%s: Close returned %v", tt.name, err)
+		}
+		if p.aborts != tt.aborts {
+			t.Errorf("%s: %d aborts, want %d", tt.name, p.aborts, tt.aborts)
+		}
+		if s.inTxn {
+			t.Errorf("%s: transaction left open", tt.name)
+		}
+
+		written, failed := s.Settled()
+		if tt.commitErr == nil && (written != 2 || failed != 0) || tt.commitErr != nil && (written != 0 || failed != 2) {
+			t.Errorf("%s: settled %d written, %d failed", tt.name, written, failed)
+		}
+	}
+}
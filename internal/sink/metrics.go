@@ -0,0 +1,26 @@
+package sink
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Per sink instrumentation, the records written / failed per sink are counted by the caller
+var (
+	batchSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "goproducer_sink_batch_size",
+		Help:    "Documents per Kafka flush / Mongo insert",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12)}, []string{"sink"})
+
+	kafkaProduced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goproducer_kafka_produced_total",
+		Help: "Messages produced onto Kafka topics"}, []string{"topic"})
+
+	kafkaDeliveryFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "goproducer_kafka_delivery_failed_total",
+		Help: "Async Kafka messages whose delivery report carried an error"})
+
+	mongoInserted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goproducer_mongo_inserted_total",
+		Help: "Documents inserted into Mongo collections"}, []string{"collection"})
+)
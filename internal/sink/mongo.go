@@ -0,0 +1,379 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"cmd/internal/money"
+	"cmd/types"
+
+	"github.com/tkanos/gonfig"
+
+	// MongoDB
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+func init() {
+	Register("mongo", func() Sink { return &mongoSink{} })
+}
+
+// mongoSink inserts the baskets, payments and refunds directly into Mongo Atlas collections, one at a time or in Batch_size batches
+type mongoSink struct {
+	cfg         Config
+	props       types.TMongodb
+	client      *mongo.Client
+	basketcol   *mongo.Collection
+	paymentcol  *mongo.Collection
+	refundcol   *mongo.Collection
+	basketdocs  []interface{}
+	paymentdocs []interface{}
+	refunddocs  []interface{}
+}
+
+func loadMongoProps(cfg Config) (types.TMongodb, error) {
+
+	vMongodb := types.TMongodb{}
+
+	path, err := os.Getwd()
+	if err != nil {
+		return vMongodb, fmt.Errorf("problem retrieving current path: %w", err)
+
+	}
+
+	fileName := fmt.Sprintf("%s/%s_mongo.json", path, cfg.Env)
+	err = gonfig.GetConf(fileName, &vMongodb)
+	if err != nil {
+		return vMongodb, fmt.Errorf("error reading Mongo file: %w", err)
+
+	}
+
+	cfg.General.MongoConfigFile = fileName
+
+	if cfg.General.Debuglevel > 0 {
+
+		cfg.Log.Info("*")
+		cfg.Log.Info("* Mongo Config :")
+		cfg.Log.Info(fmt.Sprintf("* Current path : %s", path))
+		cfg.Log.Info(fmt.Sprintf("* Mongo File   : %s", fileName))
+		cfg.Log.Info("*")
+
+	}
+
+	vMongodb.Username = os.Getenv("mongo_username")
+	vMongodb.Password = os.Getenv("mongo_password")
+
+	if vMongodb.Username != "" {
+
+		vMongodb.Uri = fmt.Sprintf("%s://%s:%s@%s&w=majority", vMongodb.Root, vMongodb.Username, vMongodb.Password, vMongodb.Url)
+
+	} else {
+
+		vMongodb.Uri = fmt.Sprintf("%s://%s&w=majority", vMongodb.Root, vMongodb.Url)
+	}
+
+	if vMongodb.Batch_size < 1 {
+		vMongodb.Batch_size = 1
+	}
+
+	if vMongodb.Refundcollection == "" {
+		vMongodb.Refundcollection = "p_salesrefunds"
+	}
+
+	if cfg.General.EchoConfig == 1 {
+		printMongoConfig(cfg, vMongodb)
+	}
+
+	return vMongodb, nil
+}
+
+// print some more configurations
+func printMongoConfig(cfg Config, vMongodb types.TMongodb) {
+
+	cfg.Log.Info("*")
+	cfg.Log.Info("****** MongoDB Connection Parameters *****")
+	cfg.Log.Info("*")
+
+	cfg.Log.Info("* Mongo URL is\t\t", vMongodb.Url)
+	cfg.Log.Info("* Mongo Port is\t\t", vMongodb.Port)
+	cfg.Log.Info("* Mongo DataStore is\t\t", vMongodb.Datastore)
+	cfg.Log.Info("* Mongo Username is\t\t", vMongodb.Username)
+	cfg.Log.Info("* Mongo Basket Collection is\t", vMongodb.Basketcollection)
+	cfg.Log.Info("* Mongo Payment Collection is\t", vMongodb.Paymentcollection)
+	cfg.Log.Info("* Mongo Refund Collection is\t", vMongodb.Refundcollection)
+	cfg.Log.Info("* Mongo Batch szie is\t\t", vMongodb.Batch_size)
+
+	cfg.Log.Info("*")
+	cfg.Log.Info("*******************************")
+
+	cfg.Log.Info("")
+
+}
+
+// Cast a byte string to BSon
+// https://stackoverflow.com/questions/39785289/how-to-marshal-json-string-to-bson-document-for-writing-to-mongodb
+// this way we don't need to care what the source structure is, it is all cast and inserted into the defined collection.
+func JsonToBson(message []byte) ([]byte, error) {
+	reader, err := bsonrw.NewExtJSONValueReader(bytes.NewReader(message), true)
+	if err != nil {
+		return []byte{}, err
+	}
+	buf := &bytes.Buffer{}
+	writer, _ := bsonrw.NewBSONValueWriter(buf)
+	err = bsonrw.Copier{}.CopyDocument(writer, reader)
+	if err != nil {
+		return []byte{}, err
+	}
+	marshaled := buf.Bytes()
+	return marshaled, nil
+}
+
+func toBson(v interface{}) ([]byte, error) {
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marchalling error: %w", err)
+
+	}
+
+	b, err = toDecimals(b)
+	if err != nil {
+		return nil, fmt.Errorf("oops, we had a problem converting the payload amounts to Decimal128, %w", err)
+
+	}
+
+	doc, err := JsonToBson(b)
+	if err != nil {
+		return nil, fmt.Errorf("oops, we had a problem JsonToBson converting the payload, %w", err)
+
+	}
+
+	return doc, nil
+}
+
+// toDecimals stores every amount that has a minor units twin, ie: total next to totalMinor, as a Decimal128 made
+// from the exact cents, so Mongo sums them without the double's rounding, the minor units stay as they are.
+func toDecimals(b []byte) ([]byte, error) {
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	if err := decimals(v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v)
+}
+
+func decimals(v interface{}) error {
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, minor := range t {
+			if !strings.HasSuffix(k, "Minor") {
+				continue
+			}
+			n, ok := minor.(json.Number)
+			if !ok {
+				return fmt.Errorf("%s is not a number", k)
+			}
+			cents, err := n.Int64()
+			if err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+			t[strings.TrimSuffix(k, "Minor")] = map[string]string{"$numberDecimal": money.Amount(cents).String()}
+		}
+		for _, e := range t {
+			if err := decimals(e); err != nil {
+				return err
+			}
+		}
+
+	case []interface{}:
+		for _, e := range t {
+			if err := decimals(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *mongoSink) Open(cfg Config) (err error) {
+
+	s.cfg = cfg
+
+	s.props, err = loadMongoProps(cfg)
+	if err != nil {
+		return err
+	}
+
+	serverAPI := options.ServerAPI(options.ServerAPIVersion1)
+
+	opts := options.Client().ApplyURI(s.props.Uri).SetServerAPIOptions(serverAPI)
+
+	cfg.Log.Infoln("* MongoDB URI Constructed: ", s.props.Uri)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s.client, err = mongo.Connect(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("mongo connect failed: %w", err)
+	}
+	cfg.Log.Infoln("* MongoDB Client Connected")
+
+	// Ping the primary
+	if err := s.client.Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("there was a error creating the Client object, Ping failed: %w", err)
+	}
+	cfg.Log.Infoln("* MongoDB Client Pinged")
+
+	// Define the Mongo Datastore
+	appLabDatabase := s.client.Database(s.props.Datastore)
+	// Define the Mongo Collection Object
+	s.basketcol = appLabDatabase.Collection(s.props.Basketcollection)
+	s.paymentcol = appLabDatabase.Collection(s.props.Paymentcollection)
+	s.refundcol = appLabDatabase.Collection(s.props.Refundcollection)
+
+	s.basketdocs = make([]interface{}, 0, s.props.Batch_size)
+	s.paymentdocs = make([]interface{}, 0, s.props.Batch_size)
+	s.refunddocs = make([]interface{}, 0, s.props.Batch_size)
+
+	if cfg.General.Debuglevel > 0 {
+		cfg.Log.Infoln("* MongoDB Datastore and Collections Intialized")
+		cfg.Log.Infoln("*")
+	}
+
+	return nil
+}
+
+func (s *mongoSink) Write(store string, basket *types.Pb_Basket, payments []*types.Pb_Payment) error {
+
+	if basket != nil {
+		doc, err := toBson(basket)
+		if err != nil {
+			return err
+		}
+		s.basketdocs = append(s.basketdocs, doc)
+	}
+
+	for _, payment := range payments {
+		doc, err := toBson(payment)
+		if err != nil {
+			return err
+		}
+		s.paymentdocs = append(s.paymentdocs, doc)
+	}
+
+	// Single Record inserts are simply batches of 1
+	if len(s.basketdocs) >= s.props.Batch_size || len(s.paymentdocs) >= s.props.Batch_size {
+		return s.Flush()
+	}
+
+	return nil
+}
+
+func (s *mongoSink) WriteRefund(refund *types.Pb_Refund) error {
+
+	doc, err := toBson(refund)
+	if err != nil {
+		return err
+	}
+	s.refunddocs = append(s.refunddocs, doc)
+
+	if len(s.refunddocs) >= s.props.Batch_size {
+		return s.Flush()
+	}
+
+	return nil
+}
+
+// insert the pending docs into the collection, InsertOne for a single doc, InsertMany for a batch
+func (s *mongoSink) insert(col *mongo.Collection, docs []interface{}, name string) error {
+
+	switch len(docs) {
+	case 0:
+		return nil
+
+	case 1:
+		// Time to get this into the MondoDB Collection
+		result, err := col.InsertOne(context.TODO(), docs[0])
+		if err != nil {
+			return fmt.Errorf("oops, we had a problem inserting (I1) the %s document, %w", name, err)
+
+		}
+
+		if s.cfg.General.Debuglevel >= 2 {
+			// Document inserted with ID: ObjectID("...")
+			s.cfg.Log.Infoln("Mongo", name, "Doc inserted with ID: ", result.InsertedID)
+
+		}
+
+	default:
+		_, err := col.InsertMany(context.TODO(), docs)
+		if err != nil {
+			return fmt.Errorf("oops, we had a problem inserting (IM) the %s documents, %w", name, err)
+
+		}
+		if s.cfg.General.Debuglevel >= 2 {
+			s.cfg.Log.Infoln("Mongo", name, "Docs inserted: ", len(docs))
+
+		}
+	}
+
+	mongoInserted.WithLabelValues(col.Name()).Add(float64(len(docs)))
+	batchSize.WithLabelValues("mongo").Observe(float64(len(docs)))
+
+	return nil
+}
+
+func (s *mongoSink) Flush() error {
+
+	// Sales Basket
+	errB := s.insert(s.basketcol, s.basketdocs, "Sales Basket")
+	s.basketdocs = s.basketdocs[:0]
+
+	// Sales Payment
+	errP := s.insert(s.paymentcol, s.paymentdocs, "Payment")
+	s.paymentdocs = s.paymentdocs[:0]
+
+	// Refunds
+	errR := s.insert(s.refundcol, s.refunddocs, "Refund")
+	s.refunddocs = s.refunddocs[:0]
+
+	if errB != nil {
+		return errB
+	}
+	if errP != nil {
+		return errP
+	}
+	return errR
+}
+
+func (s *mongoSink) Close() error {
+
+	if s.client == nil {
+		return nil
+	}
+
+	err := s.Flush()
+
+	if errD := s.client.Disconnect(context.TODO()); errD != nil && err == nil {
+		err = fmt.Errorf("mongo disconnect failed: %w", errD)
+	}
+
+	return err
+}
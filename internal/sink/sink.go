@@ -0,0 +1,74 @@
+package sink
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"cmd/types"
+
+	glog "google.golang.org/grpc/grpclog"
+)
+
+// Config is handed to every sink when it is opened, sinks load their own <env>_<sink>.json files from it.
+type Config struct {
+	Env     string            // environment name as passed on the command line, ie: pb => pb_kafka.json
+	General *types.Tp_general // the *_app.json settings for the run
+	Random  *rand.Rand        // the run's seeded random source, for sinks that need to make random choices
+	Log     glog.LoggerV2
+}
+
+// Sink interface, a destination for the baskets, payments and refunds we generate. A basket can be paid with more
+// than one tender, each its own payment, and payments can come on their own, after their basket, with a nil basket,
+// store is the name of the basket's store either way. Refunds, goods brought back after the sale, are a third stream of their own.
+type Sink interface {
+	Open(cfg Config) error
+	Write(store string, basket *types.Pb_Basket, payments []*types.Pb_Payment) error
+	WriteRefund(refund *types.Pb_Refund) error
+	Flush() error
+	Close() error
+}
+
+// Deferred is implemented by sinks that can only tell later if a write made it, once its transaction commits or its
+// delivery reports are in, a nil from Write then only means the record was taken on. When Defers, Settled returns how
+// many writes made it, and how many were lost, since it was last called, each write is counted in one of them once.
+type Deferred interface {
+	Defers() bool
+	Settled() (written, failed int)
+}
+
+// Factory returns a new, unopened, sink
+type Factory func() Sink
+
+var registry = map[string]Factory{}
+
+// Register makes a sink available by name, called from the init() of each sink implementation
+func Register(name string, factory Factory) {
+
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("sink: Register called twice for %s", name))
+	}
+	registry[name] = factory
+}
+
+// New returns a new sink for the registered name
+func New(name string) (Sink, error) {
+
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown sink %q, registered sinks are %v", name, Names())
+	}
+	return factory(), nil
+}
+
+// Names returns the registered sink names, sorted
+func Names() []string {
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,179 @@
+package tax
+
+import (
+	"fmt"
+	"strings"
+
+	"cmd/internal/money"
+	"cmd/types"
+)
+
+type rate struct {
+	code string
+	rate float64
+}
+
+// Rules is a store's country, currency and tax rules, ready to tax basket items
+type Rules struct {
+	country    string
+	currency   string
+	inclusive  bool
+	rates      []rate
+	def        int            // rates index for products no rate lists
+	byProduct  map[string]int // rates index
+	byCategory map[string]int
+}
+
+// Compile the store's tax rules, a store without rates gets vatrate on everything, exclusive of the price, and
+// a store without a currency gets currency
+func Compile(st types.TStoreStruct, seed types.TPSeed, vatrate float64, currency string) (*Rules, error) {
+
+	ru := &Rules{
+		country:    strings.ToUpper(st.Country),
+		currency:   strings.ToUpper(st.Currency),
+		inclusive:  st.Tax.Inclusive,
+		def:        -1,
+		byProduct:  make(map[string]int),
+		byCategory: make(map[string]int),
+	}
+	if ru.currency == "" {
+		ru.currency = strings.ToUpper(currency)
+	}
+	if len(ru.currency) != 3 {
+		return nil, fmt.Errorf("store %s (%s) currency %q, use an ISO 4217 code, ie: ZAR", st.Id, st.Name, ru.currency)
+	}
+
+	if len(st.Tax.Rates) == 0 {
+		ru.rates = []rate{{code: "standard", rate: vatrate}}
+		ru.def = 0
+		return ru, nil
+	}
+
+	ids := make(map[string]bool, len(seed.Products))
+	categories := make(map[string]bool)
+	for _, p := range seed.Products {
+		ids[p.Id] = true
+		categories[p.Category] = true
+	}
+
+	for i, tr := range st.Tax.Rates {
+
+		if tr.Rate < 0 || tr.Rate >= 1 {
+			return nil, fmt.Errorf("store %s (%s) tax rate %s must be between 0 and 1", st.Id, st.Name, tr.Code)
+		}
+		code := tr.Code
+		if code == "" {
+			code = fmt.Sprintf("%g%%", tr.Rate*100)
+		}
+		ru.rates = append(ru.rates, rate{code: code, rate: tr.Rate})
+
+		if len(tr.Categories) == 0 && len(tr.Products) == 0 {
+			if ru.def < 0 {
+				ru.def = i
+			}
+			continue
+		}
+
+		for _, c := range tr.Categories {
+			if !categories[c] {
+				return nil, fmt.Errorf("store %s (%s) tax rate %s: unknown category %s", st.Id, st.Name, code, c)
+			}
+			if _, ok := ru.byCategory[c]; !ok {
+				ru.byCategory[c] = i
+			}
+		}
+		for _, id := range tr.Products {
+			if !ids[id] {
+				return nil, fmt.Errorf("store %s (%s) tax rate %s: unknown product %s", st.Id, st.Name, code, id)
+			}
+			if _, ok := ru.byProduct[id]; !ok {
+				ru.byProduct[id] = i
+			}
+		}
+	}
+
+	if ru.def < 0 {
+		return nil, fmt.Errorf("store %s (%s) needs a tax rate without categories or products, for everything else", st.Id, st.Name)
+	}
+
+	return ru, nil
+}
+
+// Currency returns the ISO 4217 code the store trades in
+func (ru *Rules) Currency() string {
+	return ru.currency
+}
+
+// Country returns the ISO 3166 code of the store's country, "" if not given
+func (ru *Rules) Country() string {
+	return ru.country
+}
+
+// Inclusive tells if the store's prices include tax
+func (ru *Rules) Inclusive() bool {
+	return ru.inclusive
+}
+
+func (ru *Rules) rateOf(item *types.BasketItem) int {
+
+	if i, ok := ru.byProduct[item.Id]; ok {
+		return i
+	}
+	if i, ok := ru.byCategory[item.Category]; ok {
+		return i
+	}
+	return ru.def
+}
+
+// Apply taxes the items, price x quantity - discount, setting each one's tax code, rate and tax. The totals are taxed
+// per rate, so nett and vat are what a till would print, the lines' taxes can be a cent or so off their sum. Nett + vat
+// is the total, to the cent, with inclusive prices that is what the items cost.
+func (ru *Rules) Apply(items []*types.BasketItem) (nett money.Amount, vat money.Amount, taxes []*types.TaxLine) {
+
+	amounts := make([]money.Amount, len(ru.rates))
+	used := make([]bool, len(ru.rates))
+
+	for _, item := range items {
+
+		i := ru.rateOf(item)
+		amount := money.Amount(item.PriceMinor).Times(int(item.Quantity)) - money.Amount(item.DiscountMinor)
+		tax := ru.tax(amount, ru.rates[i].rate)
+
+		item.TaxCode = ru.rates[i].code
+		item.TaxRate = ru.rates[i].rate
+		item.TaxMinor = int64(tax)
+		item.Tax = tax.Float()
+
+		amounts[i] += amount
+		used[i] = true
+	}
+
+	for i, r := range ru.rates {
+		if !used[i] {
+			continue
+		}
+
+		tax := ru.tax(amounts[i], r.rate)
+		taxable := amounts[i]
+		if ru.inclusive {
+			taxable -= tax
+		}
+
+		taxes = append(taxes, &types.TaxLine{Code: r.code, Rate: r.rate,
+			Taxable: taxable.Float(), Tax: tax.Float(), TaxableMinor: int64(taxable), TaxMinor: int64(tax)})
+
+		nett += taxable
+		vat += tax
+	}
+
+	return nett, vat, taxes
+}
+
+// tax on amount, which includes it when prices are tax inclusive
+func (ru *Rules) tax(amount money.Amount, rate float64) money.Amount {
+
+	if ru.inclusive {
+		return amount.Mul(rate / (1 + rate))
+	}
+	return amount.Mul(rate)
+}
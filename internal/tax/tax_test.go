@@ -0,0 +1,98 @@
+package tax
+
+import (
+	"testing"
+
+	"cmd/internal/money"
+	"cmd/types"
+)
+
+func TestApply(t *testing.T) {
+
+	seed := types.TPSeed{Products: []types.TProductStruct{
+		{Id: "1", Category: "Food"},
+		{Id: "2", Category: "Cleaning"},
+	}}
+
+	tests := []struct {
+		name      string
+		tax       types.TTaxRules
+		items     []*types.BasketItem
+		nett, vat money.Amount
+		itemTax   []int64 // per item
+		lines     int
+	}{
+		{
+			// 5997 + 1098 = 7095 taxed 993.3, the items on their own 839.58 + 153.72, a cent more than the total
+			name:    "exclusive, lines a cent off the total",
+			items:   []*types.BasketItem{{Id: "1", Category: "Food", PriceMinor: 1999, Quantity: 3}, {Id: "2", Category: "Cleaning", PriceMinor: 549, Quantity: 2}},
+			nett:    7095,
+			vat:     993,
+			itemTax: []int64{840, 154},
+			lines:   1,
+		},
+		{
+			// 10499 x 5 at 10% is 5249.5
+			name:    "exclusive, half cent rounds up",
+			tax:     types.TTaxRules{Rates: []types.TTaxRate{{Code: "standard", Rate: 0.10}}},
+			items:   []*types.BasketItem{{Id: "1", Category: "Food", PriceMinor: 10499, Quantity: 5}},
+			nett:    52495,
+			vat:     5250,
+			itemTax: []int64{5250},
+			lines:   1,
+		},
+		{
+			// 2149 includes 280.30 tax
+			name:    "inclusive, nett + vat is what the items cost",
+			tax:     types.TTaxRules{Inclusive: true, Rates: []types.TTaxRate{{Code: "standard", Rate: 0.15}}},
+			items:   []*types.BasketItem{{Id: "1", Category: "Food", PriceMinor: 1150, Quantity: 1}, {Id: "2", Category: "Cleaning", PriceMinor: 999, Quantity: 1}},
+			nett:    1869,
+			vat:     280,
+			itemTax: []int64{150, 130},
+			lines:   1,
+		},
+		{
+			// food zero rated after its discount, 999 at 15% is 149.85
+			name: "per category rates and discounts",
+			tax: types.TTaxRules{Rates: []types.TTaxRate{
+				{Code: "standard", Rate: 0.15},
+				{Code: "zero", Rate: 0, Categories: []string{"Food"}},
+			}},
+			items:   []*types.BasketItem{{Id: "1", Category: "Food", PriceMinor: 1000, Quantity: 2, DiscountMinor: 100}, {Id: "2", Category: "Cleaning", PriceMinor: 333, Quantity: 3}},
+			nett:    2899,
+			vat:     150,
+			itemTax: []int64{0, 150},
+			lines:   2,
+		},
+	}
+
+	for _, tt := range tests {
+
+		ru, err := Compile(types.TStoreStruct{Id: "1", Name: "test", Tax: tt.tax}, seed, 0.14, "ZAR")
+		if err != nil {
+			t.Fatalf("%s: Compile: %v", tt.name, err)
+		}
+
+		nett, vat, taxes := ru.Apply(tt.items)
+		if nett != tt.nett || vat != tt.vat {
+			t.Errorf("%s: nett, vat = %d, %d, want %d, %d", tt.name, nett, vat, tt.nett, tt.vat)
+		}
+		for i, item := range tt.items {
+			if item.TaxMinor != tt.itemTax[i] {
+				t.Errorf("%s: item %d tax = %d, want %d", tt.name, i, item.TaxMinor, tt.itemTax[i])
+			}
+		}
+
+		if len(taxes) != tt.lines {
+			t.Fatalf("%s: %d tax lines, want %d", tt.name, len(taxes), tt.lines)
+		}
+		var taxable, tax int64
+		for _, line := range taxes {
+			taxable += line.TaxableMinor
+			tax += line.TaxMinor
+		}
+		if taxable != int64(nett) || tax != int64(vat) {
+			t.Errorf("%s: tax lines add up to %d, %d, want %d, %d", tt.name, taxable, tax, nett, vat)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package tender
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"cmd/internal/money"
+	"cmd/types"
+)
+
+// Types of tender a basket can be paid with, in the order weights are read, so a seed replays the same picks
+var Types = []string{"cash", "card", "eft", "voucher", "loyalty"}
+
+// Mix picks a tender type in proportion to its weight
+type Mix struct {
+	types      []string
+	cumulative []float64
+}
+
+// NewMix builds a mix from weights by tender type, ie: {"cash": 30, "card": 60, "eft": 10}
+func NewMix(weights map[string]float64) (*Mix, error) {
+
+	known := make(map[string]bool, len(Types))
+	for _, t := range Types {
+		known[t] = true
+	}
+	for t, w := range weights {
+		if !known[strings.ToLower(t)] {
+			return nil, fmt.Errorf("unknown tender %q, use one of %s", t, strings.Join(Types, ", "))
+		}
+		if w < 0 {
+			return nil, fmt.Errorf("tender %s has a negative weight", t)
+		}
+	}
+
+	m := &Mix{}
+	total := 0.0
+	for _, t := range Types {
+		for name, w := range weights {
+			if strings.ToLower(name) == t && w > 0 {
+				total += w
+				m.types = append(m.types, t)
+				m.cumulative = append(m.cumulative, total)
+			}
+		}
+	}
+
+	if total <= 0 {
+		return nil, fmt.Errorf("tender weights add up to 0")
+	}
+
+	return m, nil
+}
+
+// Pick a tender type
+func (m *Mix) Pick(r *rand.Rand) string {
+
+	x := r.Float64() * m.cumulative[len(m.cumulative)-1]
+	for i, c := range m.cumulative {
+		if x < c {
+			return m.types[i]
+		}
+	}
+	return m.types[len(m.types)-1]
+}
+
+// Split divides amount into n random parts, at least a cent each, that add up to it exactly
+func Split(r *rand.Rand, amount money.Amount, n int) []money.Amount {
+
+	if money.Amount(n) > amount {
+		n = int(amount)
+	}
+	if n <= 1 {
+		return []money.Amount{amount}
+	}
+
+	// every part gets at least a cent, the rest is shared out at random
+	weights := make([]float64, n)
+	sum := 0.0
+	for i := range weights {
+		weights[i] = r.Float64() + 0.1
+		sum += weights[i]
+	}
+
+	parts := make([]money.Amount, n)
+	left := amount - money.Amount(n)
+	remaining := amount
+	for i := 0; i < n-1; i++ {
+		parts[i] = 1 + money.Amount(float64(left)*weights[i]/sum)
+		remaining -= parts[i]
+	}
+	parts[n-1] = remaining
+
+	return parts
+}
+
+var schemes = []struct {
+	name     string
+	weight   float64
+	prefixes []string
+	length   int
+}{
+	{"visa", 55, []string{"4"}, 16},
+	{"mastercard", 40, []string{"51", "52", "53", "54", "55"}, 16},
+	{"amex", 5, []string{"34", "37"}, 15},
+}
+
+// Card returns a random card, its scheme and PAN masked to the first 6 and last 4 digits
+func Card(r *rand.Rand) *types.CardDetail {
+
+	x := r.Float64() * 100
+	s := schemes[len(schemes)-1]
+	for _, sc := range schemes {
+		if x < sc.weight {
+			s = sc
+			break
+		}
+		x -= sc.weight
+	}
+
+	var pan strings.Builder
+	pan.WriteString(s.prefixes[r.Intn(len(s.prefixes))])
+	for pan.Len() < s.length {
+		pan.WriteByte(byte('0' + r.Intn(10)))
+	}
+	digits := pan.String()
+
+	return &types.CardDetail{
+		Scheme:    s.name,
+		MaskedPan: digits[:6] + strings.Repeat("*", s.length-10) + digits[s.length-4:],
+	}
+}
+
+var notes = []money.Amount{1000, 2000, 5000, 10000, 20000}
+
+// Cash returns what the customer hands over for amount, the exact amount now and again, otherwise the smallest
+// note covering it, or 100s for larger amounts, and the change given
+func Cash(r *rand.Rand, amount money.Amount) (tendered money.Amount, change money.Amount) {
+
+	tendered = amount
+	if r.Float64() >= 0.2 {
+		tendered = (amount + 9999) / 10000 * 10000
+		for _, n := range notes {
+			if n >= amount {
+				tendered = n
+				break
+			}
+		}
+	}
+
+	return tendered, tendered - amount
+}
+
+var declineReasons = []string{"insufficient funds", "do not honour", "expired card", "incorrect pin", "limit exceeded"}
+
+var failureReasons = []string{"timeout", "host unavailable", "communication error"}
+
+// DeclineReason returns why the bank turned an attempt down
+func DeclineReason(r *rand.Rand) string {
+	return declineReasons[r.Intn(len(declineReasons))]
+}
+
+// FailureReason returns why an attempt never reached the bank
+func FailureReason(r *rand.Rand) string {
+	return failureReasons[r.Intn(len(failureReasons))]
+}
@@ -0,0 +1,47 @@
+package tender
+
+import (
+	"math/rand"
+	"testing"
+
+	"cmd/internal/money"
+)
+
+func TestSplit(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		amount money.Amount
+		n      int
+		parts  int
+	}{
+		{"one tender", 16831, 1, 1},
+		{"even", 10000, 4, 4},
+		{"remainder", 100, 3, 3},
+		{"odd cents", 76867, 5, 5},
+		{"as many parts as cents", 3, 3, 3},
+		{"more tenders than cents", 2, 5, 2},
+		{"nothing to pay", 0, 3, 1},
+	}
+
+	for _, tt := range tests {
+		for seed := int64(1); seed <= 100; seed++ {
+
+			parts := Split(rand.New(rand.NewSource(seed)), tt.amount, tt.n)
+			if len(parts) != tt.parts {
+				t.Fatalf("%s: Split(%d, %d) gave %d parts, want %d", tt.name, tt.amount, tt.n, len(parts), tt.parts)
+			}
+
+			var sum money.Amount
+			for _, p := range parts {
+				if p < 1 && tt.amount > 0 {
+					t.Errorf("%s, seed %d: part of %d, every part should be at least a cent", tt.name, seed, p)
+				}
+				sum += p
+			}
+			if sum != tt.amount {
+				t.Errorf("%s, seed %d: parts %v add up to %d, want %d", tt.name, seed, parts, sum, tt.amount)
+			}
+		}
+	}
+}
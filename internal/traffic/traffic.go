@@ -0,0 +1,164 @@
+package traffic
+
+import (
+	"fmt"
+	"time"
+
+	"cmd/types"
+)
+
+// curve is a multiplier per hour of the day and per day of the week, the two multiply
+type curve struct {
+	hours    [24]float64
+	weekdays [7]float64
+}
+
+func newCurve(hours []float64, weekdays []float64) (curve, error) {
+
+	var c curve
+
+	switch len(hours) {
+	case 0:
+		for i := range c.hours {
+			c.hours[i] = 1
+		}
+	case 24:
+		copy(c.hours[:], hours)
+	default:
+		return c, fmt.Errorf("Hours needs 24 multipliers (00:00 to 23:00), got %d", len(hours))
+	}
+
+	switch len(weekdays) {
+	case 0:
+		for i := range c.weekdays {
+			c.weekdays[i] = 1
+		}
+	case 7:
+		copy(c.weekdays[:], weekdays)
+	default:
+		return c, fmt.Errorf("Weekdays needs 7 multipliers (Sunday to Saturday), got %d", len(weekdays))
+	}
+
+	for _, v := range append(c.hours[:], c.weekdays[:]...) {
+		if v < 0 {
+			return c, fmt.Errorf("multipliers must be >= 0, got %v", v)
+		}
+	}
+
+	return c, nil
+}
+
+func (c curve) at(hour int, weekday time.Weekday) float64 {
+	return c.hours[hour] * c.weekdays[weekday]
+}
+
+// Shape scales the basket arrival rate by the time of day and day of the week, overall and per store.
+// Each store's arrivals follow the overall curve times its own, the overall rate is therefore the
+// overall curve times the average of the store curves.
+type Shape struct {
+	overall curve
+	stores  map[string]curve // by store id, stores without one are flat
+	ids     []string         // every store we pick from
+	max     float64
+}
+
+// New builds the shape for the given stores (by id) from the TrafficShape configuration
+func New(cfg types.TTrafficShape, storeIds []string) (*Shape, error) {
+
+	overall, err := newCurve(cfg.Hours, cfg.Weekdays)
+	if err != nil {
+		return nil, fmt.Errorf("traffic shape: %w", err)
+	}
+
+	s := &Shape{overall: overall, stores: make(map[string]curve), ids: storeIds}
+
+	known := make(map[string]bool, len(storeIds))
+	for _, id := range storeIds {
+		known[id] = true
+	}
+
+	for _, st := range cfg.Stores {
+		if !known[st.Id] {
+			return nil, fmt.Errorf("traffic shape for store %s: no such store", st.Id)
+		}
+		c, err := newCurve(st.Hours, st.Weekdays)
+		if err != nil {
+			return nil, fmt.Errorf("traffic shape for store %s: %w", st.Id, err)
+		}
+		s.stores[st.Id] = c
+	}
+
+	// the peak over the week, needed to thin arrivals
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		for h := 0; h < 24; h++ {
+			if f := s.factorAt(h, d); f > s.max {
+				s.max = f
+			}
+		}
+	}
+
+	if s.max <= 0 {
+		return nil, fmt.Errorf("traffic shape: every multiplier is 0, nothing would ever be sold")
+	}
+
+	return s, nil
+}
+
+func (s *Shape) factorAt(hour int, weekday time.Weekday) float64 {
+
+	f := s.overall.at(hour, weekday)
+
+	if len(s.stores) > 0 && len(s.ids) > 0 {
+		sum := 0.0
+		for _, id := range s.ids {
+			sum += s.storeAt(id, hour, weekday)
+		}
+		f *= sum / float64(len(s.ids))
+	}
+
+	return f
+}
+
+func (s *Shape) storeAt(id string, hour int, weekday time.Weekday) float64 {
+
+	c, ok := s.stores[id]
+	if !ok {
+		return 1
+	}
+	return c.at(hour, weekday)
+}
+
+// Factor returns the multiplier for the overall arrival rate at t
+func (s *Shape) Factor(t time.Time) float64 {
+	return s.factorAt(t.Hour(), t.Weekday())
+}
+
+// Max returns the largest Factor over the week
+func (s *Shape) Max() float64 {
+	return s.max
+}
+
+// StoreFactor returns the store's own multiplier at t, its weight when picking the store for a basket
+func (s *Shape) StoreFactor(id string, t time.Time) float64 {
+	return s.storeAt(id, t.Hour(), t.Weekday())
+}
+
+// PerStore tells if any store has a curve of its own
+func (s *Shape) PerStore() bool {
+	return len(s.stores) > 0
+}
+
+// Resume returns t if Factor is above 0 at t, otherwise the start of the first hour after t where it is
+func (s *Shape) Resume(t time.Time) time.Time {
+
+	if s.Factor(t) > 0 {
+		return t
+	}
+
+	// max > 0, so there is such an hour within the week
+	next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+	for i := 0; i < 7*24 && s.Factor(next) <= 0; i++ {
+		next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+	}
+	return next
+}
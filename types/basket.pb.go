@@ -25,12 +25,20 @@ type BasketItem struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name     string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Brand    string  `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
-	Category string  `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	Price    float64 `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
-	Quantity int32   `protobuf:"varint,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Id            string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Brand         string  `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
+	Category      string  `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Price         float64 `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      int32   `protobuf:"varint,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Discount      float64 `protobuf:"fixed64,7,opt,name=discount,proto3" json:"discount,omitempty"` // off price x quantity
+	Promotion     string  `protobuf:"bytes,8,opt,name=promotion,proto3" json:"promotion,omitempty"` // the promotion giving the discount
+	TaxCode       string  `protobuf:"bytes,9,opt,name=taxCode,proto3" json:"taxCode,omitempty"`     // the store's tax rate the item falls under
+	TaxRate       float64 `protobuf:"fixed64,10,opt,name=taxRate,proto3" json:"taxRate,omitempty"`
+	Tax           float64 `protobuf:"fixed64,11,opt,name=tax,proto3" json:"tax,omitempty"`              // on the line, after discount
+	PriceMinor    int64   `protobuf:"varint,12,opt,name=priceMinor,proto3" json:"priceMinor,omitempty"` // price, discount and tax in minor units (cents), exact
+	DiscountMinor int64   `protobuf:"varint,13,opt,name=discountMinor,proto3" json:"discountMinor,omitempty"`
+	TaxMinor      int64   `protobuf:"varint,14,opt,name=taxMinor,proto3" json:"taxMinor,omitempty"`
 }
 
 func (x *BasketItem) Reset() {
@@ -107,6 +115,236 @@ func (x *BasketItem) GetQuantity() int32 {
 	return 0
 }
 
+func (x *BasketItem) GetDiscount() float64 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *BasketItem) GetPromotion() string {
+	if x != nil {
+		return x.Promotion
+	}
+	return ""
+}
+
+func (x *BasketItem) GetTaxCode() string {
+	if x != nil {
+		return x.TaxCode
+	}
+	return ""
+}
+
+func (x *BasketItem) GetTaxRate() float64 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *BasketItem) GetTax() float64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+func (x *BasketItem) GetPriceMinor() int64 {
+	if x != nil {
+		return x.PriceMinor
+	}
+	return 0
+}
+
+func (x *BasketItem) GetDiscountMinor() int64 {
+	if x != nil {
+		return x.DiscountMinor
+	}
+	return 0
+}
+
+func (x *BasketItem) GetTaxMinor() int64 {
+	if x != nil {
+		return x.TaxMinor
+	}
+	return 0
+}
+
+type TaxLine struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code         string  `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Rate         float64 `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	Taxable      float64 `protobuf:"fixed64,3,opt,name=taxable,proto3" json:"taxable,omitempty"` // nett amount taxed at the rate
+	Tax          float64 `protobuf:"fixed64,4,opt,name=tax,proto3" json:"tax,omitempty"`
+	TaxableMinor int64   `protobuf:"varint,5,opt,name=taxableMinor,proto3" json:"taxableMinor,omitempty"` // taxable and tax in minor units (cents), exact
+	TaxMinor     int64   `protobuf:"varint,6,opt,name=taxMinor,proto3" json:"taxMinor,omitempty"`
+}
+
+func (x *TaxLine) Reset() {
+	*x = TaxLine{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_basket_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaxLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaxLine) ProtoMessage() {}
+
+func (x *TaxLine) ProtoReflect() protoreflect.Message {
+	mi := &file_basket_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaxLine.ProtoReflect.Descriptor instead.
+func (*TaxLine) Descriptor() ([]byte, []int) {
+	return file_basket_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TaxLine) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *TaxLine) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *TaxLine) GetTaxable() float64 {
+	if x != nil {
+		return x.Taxable
+	}
+	return 0
+}
+
+func (x *TaxLine) GetTax() float64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+func (x *TaxLine) GetTaxableMinor() int64 {
+	if x != nil {
+		return x.TaxableMinor
+	}
+	return 0
+}
+
+func (x *TaxLine) GetTaxMinor() int64 {
+	if x != nil {
+		return x.TaxMinor
+	}
+	return 0
+}
+
+type Customer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	LoyaltyId string `protobuf:"bytes,2,opt,name=loyaltyId,proto3" json:"loyaltyId,omitempty"`
+	Name      string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Email     string `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	HomeStore string `protobuf:"bytes,5,opt,name=homeStore,proto3" json:"homeStore,omitempty"` // store id
+	Segment   string `protobuf:"bytes,6,opt,name=segment,proto3" json:"segment,omitempty"`     // budget, regular or premium
+}
+
+func (x *Customer) Reset() {
+	*x = Customer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_basket_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Customer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Customer) ProtoMessage() {}
+
+func (x *Customer) ProtoReflect() protoreflect.Message {
+	mi := &file_basket_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Customer.ProtoReflect.Descriptor instead.
+func (*Customer) Descriptor() ([]byte, []int) {
+	return file_basket_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Customer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Customer) GetLoyaltyId() string {
+	if x != nil {
+		return x.LoyaltyId
+	}
+	return ""
+}
+
+func (x *Customer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Customer) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Customer) GetHomeStore() string {
+	if x != nil {
+		return x.HomeStore
+	}
+	return ""
+}
+
+func (x *Customer) GetSegment() string {
+	if x != nil {
+		return x.Segment
+	}
+	return ""
+}
+
 type Idstruct struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -119,7 +357,7 @@ type Idstruct struct {
 func (x *Idstruct) Reset() {
 	*x = Idstruct{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_basket_proto_msgTypes[1]
+		mi := &file_basket_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -132,7 +370,7 @@ func (x *Idstruct) String() string {
 func (*Idstruct) ProtoMessage() {}
 
 func (x *Idstruct) ProtoReflect() protoreflect.Message {
-	mi := &file_basket_proto_msgTypes[1]
+	mi := &file_basket_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -145,7 +383,7 @@ func (x *Idstruct) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Idstruct.ProtoReflect.Descriptor instead.
 func (*Idstruct) Descriptor() ([]byte, []int) {
-	return file_basket_proto_rawDescGZIP(), []int{1}
+	return file_basket_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Idstruct) GetId() string {
@@ -177,12 +415,22 @@ type Pb_Basket struct {
 	Nett          float64       `protobuf:"fixed64,8,opt,name=nett,proto3" json:"nett,omitempty"`
 	Vat           float64       `protobuf:"fixed64,9,opt,name=vat,proto3" json:"vat,omitempty"`
 	Total         float64       `protobuf:"fixed64,10,opt,name=total,proto3" json:"total,omitempty"`
+	Discount      float64       `protobuf:"fixed64,11,opt,name=discount,proto3" json:"discount,omitempty"`        // of all the basket items, nett is after discount
+	Customer      *Customer     `protobuf:"bytes,12,opt,name=customer,proto3" json:"customer,omitempty"`          // loyalty customer, unset for anonymous sales
+	Currency      string        `protobuf:"bytes,13,opt,name=currency,proto3" json:"currency,omitempty"`          // ISO 4217, all the amounts are in it
+	Country       string        `protobuf:"bytes,14,opt,name=country,proto3" json:"country,omitempty"`            // ISO 3166, where the store is
+	TaxInclusive  bool          `protobuf:"varint,15,opt,name=taxInclusive,proto3" json:"taxInclusive,omitempty"` // the item prices include tax
+	Taxes         []*TaxLine    `protobuf:"bytes,16,rep,name=taxes,proto3" json:"taxes,omitempty"`                // vat broken down by tax rate
+	NettMinor     int64         `protobuf:"varint,17,opt,name=nettMinor,proto3" json:"nettMinor,omitempty"`       // the amounts in minor units (cents), exact, totalMinor == nettMinor + vatMinor
+	VatMinor      int64         `protobuf:"varint,18,opt,name=vatMinor,proto3" json:"vatMinor,omitempty"`
+	TotalMinor    int64         `protobuf:"varint,19,opt,name=totalMinor,proto3" json:"totalMinor,omitempty"`
+	DiscountMinor int64         `protobuf:"varint,20,opt,name=discountMinor,proto3" json:"discountMinor,omitempty"`
 }
 
 func (x *Pb_Basket) Reset() {
 	*x = Pb_Basket{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_basket_proto_msgTypes[2]
+		mi := &file_basket_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -195,7 +443,7 @@ func (x *Pb_Basket) String() string {
 func (*Pb_Basket) ProtoMessage() {}
 
 func (x *Pb_Basket) ProtoReflect() protoreflect.Message {
-	mi := &file_basket_proto_msgTypes[2]
+	mi := &file_basket_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -208,7 +456,7 @@ func (x *Pb_Basket) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Pb_Basket.ProtoReflect.Descriptor instead.
 func (*Pb_Basket) Descriptor() ([]byte, []int) {
-	return file_basket_proto_rawDescGZIP(), []int{2}
+	return file_basket_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Pb_Basket) GetInvoiceNumber() string {
@@ -281,11 +529,81 @@ func (x *Pb_Basket) GetTotal() float64 {
 	return 0
 }
 
+func (x *Pb_Basket) GetDiscount() float64 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *Pb_Basket) GetCustomer() *Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+func (x *Pb_Basket) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Pb_Basket) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Pb_Basket) GetTaxInclusive() bool {
+	if x != nil {
+		return x.TaxInclusive
+	}
+	return false
+}
+
+func (x *Pb_Basket) GetTaxes() []*TaxLine {
+	if x != nil {
+		return x.Taxes
+	}
+	return nil
+}
+
+func (x *Pb_Basket) GetNettMinor() int64 {
+	if x != nil {
+		return x.NettMinor
+	}
+	return 0
+}
+
+func (x *Pb_Basket) GetVatMinor() int64 {
+	if x != nil {
+		return x.VatMinor
+	}
+	return 0
+}
+
+func (x *Pb_Basket) GetTotalMinor() int64 {
+	if x != nil {
+		return x.TotalMinor
+	}
+	return 0
+}
+
+func (x *Pb_Basket) GetDiscountMinor() int64 {
+	if x != nil {
+		return x.DiscountMinor
+	}
+	return 0
+}
+
 var File_basket_proto protoreflect.FileDescriptor
 
 var file_basket_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
-	0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0x94, 0x01, 0x0a, 0x0a, 0x42, 0x61, 0x73, 0x6b, 0x65, 0x74,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0xf6, 0x02, 0x0a, 0x0a, 0x42, 0x61, 0x73, 0x6b, 0x65, 0x74,
 	0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x72, 0x61, 0x6e,
@@ -294,34 +612,88 @@ var file_basket_proto_rawDesc = []byte{
 	0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72,
 	0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65,
 	0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x2e, 0x0a, 0x08,
-	0x49, 0x64, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xe0, 0x02, 0x0a,
-	0x09, 0x50, 0x62, 0x5f, 0x42, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e,
-	0x76, 0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0d, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72,
-	0x12, 0x22, 0x0a, 0x0c, 0x73, 0x61, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x61, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x65,
-	0x54, 0x69, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x61, 0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x61, 0x6c,
-	0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x25, 0x0a, 0x05, 0x73, 0x74,
-	0x6f, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65,
-	0x73, 0x2e, 0x49, 0x64, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72,
-	0x65, 0x12, 0x25, 0x0a, 0x05, 0x63, 0x6c, 0x65, 0x72, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x64, 0x73, 0x74, 0x72, 0x75, 0x63,
-	0x74, 0x52, 0x05, 0x63, 0x6c, 0x65, 0x72, 0x6b, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x65, 0x72, 0x6d,
-	0x69, 0x6e, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0d, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x33,
-	0x0a, 0x0b, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x07, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x42, 0x61, 0x73, 0x6b,
-	0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x0b, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x49, 0x74,
-	0x65, 0x6d, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x65, 0x74, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
-	0x01, 0x52, 0x04, 0x6e, 0x65, 0x74, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x61, 0x74, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x76, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74,
-	0x61, 0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42,
-	0x11, 0x5a, 0x0f, 0x2e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x2e,
-	0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x28, 0x05, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x6d,
+	0x6f, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f,
+	0x6d, 0x6f, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61, 0x78, 0x43, 0x6f, 0x64,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x61, 0x78, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x74, 0x61, 0x78, 0x52, 0x61, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x07, 0x74, 0x61, 0x78, 0x52, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61,
+	0x78, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x74, 0x61, 0x78, 0x12, 0x1e, 0x0a, 0x0a,
+	0x70, 0x72, 0x69, 0x63, 0x65, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0a, 0x70, 0x72, 0x69, 0x63, 0x65, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x12, 0x24, 0x0a, 0x0d,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x69, 0x6e,
+	0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x61, 0x78, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x74, 0x61, 0x78, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x22, 0x9d,
+	0x01, 0x0a, 0x07, 0x54, 0x61, 0x78, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x72, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x72, 0x61,
+	0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61, 0x78, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x07, 0x74, 0x61, 0x78, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x74, 0x61, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x74, 0x61, 0x78, 0x12, 0x22,
+	0x0a, 0x0c, 0x74, 0x61, 0x78, 0x61, 0x62, 0x6c, 0x65, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x74, 0x61, 0x78, 0x61, 0x62, 0x6c, 0x65, 0x4d, 0x69, 0x6e,
+	0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x61, 0x78, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x74, 0x61, 0x78, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x22, 0x9a,
+	0x01, 0x0a, 0x08, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x6c,
+	0x6f, 0x79, 0x61, 0x6c, 0x74, 0x79, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x6c, 0x6f, 0x79, 0x61, 0x6c, 0x74, 0x79, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d,
+	0x61, 0x69, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x6f, 0x6d, 0x65, 0x53, 0x74, 0x6f, 0x72, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x68, 0x6f, 0x6d, 0x65, 0x53, 0x74, 0x6f, 0x72,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x2e, 0x0a, 0x08, 0x49,
+	0x64, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xa9, 0x05, 0x0a, 0x09,
+	0x50, 0x62, 0x5f, 0x42, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x76,
+	0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12,
+	0x22, 0x0a, 0x0c, 0x73, 0x61, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x61, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x61, 0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x61, 0x6c, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x25, 0x0a, 0x05, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73,
+	0x2e, 0x49, 0x64, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x12, 0x25, 0x0a, 0x05, 0x63, 0x6c, 0x65, 0x72, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x64, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74,
+	0x52, 0x05, 0x63, 0x6c, 0x65, 0x72, 0x6b, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x33, 0x0a,
+	0x0b, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x42, 0x61, 0x73, 0x6b, 0x65,
+	0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x0b, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x49, 0x74, 0x65,
+	0x6d, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x65, 0x74, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x04, 0x6e, 0x65, 0x74, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x61, 0x74, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x03, 0x76, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x1a,
+	0x0a, 0x08, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x08, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x08, 0x63, 0x75,
+	0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x2e, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x52, 0x08, 0x63,
+	0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x22, 0x0a,
+	0x0c, 0x74, 0x61, 0x78, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0c, 0x74, 0x61, 0x78, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76,
+	0x65, 0x12, 0x24, 0x0a, 0x05, 0x74, 0x61, 0x78, 0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x78, 0x4c, 0x69, 0x6e, 0x65,
+	0x52, 0x05, 0x74, 0x61, 0x78, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x65, 0x74, 0x74, 0x4d,
+	0x69, 0x6e, 0x6f, 0x72, 0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x65, 0x74, 0x74,
+	0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x76, 0x61, 0x74, 0x4d, 0x69, 0x6e, 0x6f,
+	0x72, 0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x76, 0x61, 0x74, 0x4d, 0x69, 0x6e, 0x6f,
+	0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18,
+	0x13, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x69, 0x6e, 0x6f,
+	0x72, 0x12, 0x24, 0x0a, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x69, 0x6e,
+	0x6f, 0x72, 0x18, 0x14, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x42, 0x03, 0x5a, 0x01, 0x2e, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -336,21 +708,25 @@ func file_basket_proto_rawDescGZIP() []byte {
 	return file_basket_proto_rawDescData
 }
 
-var file_basket_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_basket_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_basket_proto_goTypes = []interface{}{
 	(*BasketItem)(nil), // 0: types.BasketItem
-	(*Idstruct)(nil),   // 1: types.Idstruct
-	(*Pb_Basket)(nil),  // 2: types.Pb_Basket
+	(*TaxLine)(nil),    // 1: types.TaxLine
+	(*Customer)(nil),   // 2: types.Customer
+	(*Idstruct)(nil),   // 3: types.Idstruct
+	(*Pb_Basket)(nil),  // 4: types.Pb_Basket
 }
 var file_basket_proto_depIdxs = []int32{
-	1, // 0: types.Pb_Basket.store:type_name -> types.Idstruct
-	1, // 1: types.Pb_Basket.clerk:type_name -> types.Idstruct
+	3, // 0: types.Pb_Basket.store:type_name -> types.Idstruct
+	3, // 1: types.Pb_Basket.clerk:type_name -> types.Idstruct
 	0, // 2: types.Pb_Basket.basketItems:type_name -> types.BasketItem
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2, // 3: types.Pb_Basket.customer:type_name -> types.Customer
+	1, // 4: types.Pb_Basket.taxes:type_name -> types.TaxLine
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_basket_proto_init() }
@@ -372,7 +748,7 @@ func file_basket_proto_init() {
 			}
 		}
 		file_basket_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Idstruct); i {
+			switch v := v.(*TaxLine); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -384,6 +760,30 @@ func file_basket_proto_init() {
 			}
 		}
 		file_basket_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Customer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_basket_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Idstruct); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_basket_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Pb_Basket); i {
 			case 0:
 				return &v.state
@@ -402,7 +802,7 @@ func file_basket_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_basket_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -5,23 +5,97 @@ type Tp_general struct {
 	EchoConfig        int
 	Hostname          string
 	Debuglevel        int
-	Testsize          int     // Used to limit number of records posted, over rided when reading test cases from input_source,
-	Sleep             int     // sleep time between Basket Create and Payment post
-	SeedFile          string  // Which seed file to read in
-	EchoSeed          int     // 0/1 Echo the seed data to terminal
-	CurrentPath       string  // current
-	OSName            string  // OS name
-	Vatrate           float64 // Amount
-	Store             int     // if <> 0 then store at that position in array is selected.
-	KafkaEnabled      int     // if = 1 then post docs to kafka
-	MongoAtlasEnabled int     // if = 1 then post docs to MongoDB
-	Json_to_file      int     // do we spool the created baskets and payments to a file/s
-	Output_path       string  // if yes above then pipe json here. we will spool the baskets to one file and the payments to a second.
-	TimeOffset        string  // what offset do we run with, from GMT / Zulu time
-	Max_items_basket  int     // max items in a basket
-	Max_quantity      int     // max quantity of items in a basket per product
-	KafkaConfigFile   string  // Kafka configuration file
-	MongoConfigFile   string  // Mongo configuration file
+	Testsize          int                // Used to limit number of records posted, over rided when reading test cases from input_source,
+	RandomSeed        int64              // Seed for the random source driving all picks, 0 => seeded from the clock, anything else makes a run replayable
+	Sleep             int                // sleep time between Basket Create and Payment post
+	TargetTps         float64            // events (basket + payment) per second, when > 0, or when a LoadProfile is given, replaces the Sleep pacing
+	LoadProfile       []TLoadStage       // stages run in order, the run ends when the last stage completes
+	TrafficShape      TTrafficShape      // hour of day / day of week multipliers on the arrival rate, overall and per store
+	Workers           int                // number of goroutines building baskets/payments, default 1
+	BackfillStart     string             // if set we generate history from here on a simulated clock, ie: 2026-09-01, 2026-09-01T08:00:00 or RFC3339
+	BackfillEnd       string             // where the backfill stops, default now
+	BackfillTps       float64            // arrival rate in baskets per simulated second during a backfill, default 1
+	ShutdownTimeout   int                // seconds the sinks get to flush and close on shutdown (end of run or SIGINT/SIGTERM), default 30
+	Max_payment_delay string             // time.ParseDuration format, payments happen between 0 and this long after the sale, default 5m59s
+	DelayPayments     int                // 1 = payments are emitted once their pay time comes around instead of straight after the basket
+	TimeCompression   float64            // how much faster than real time the payment delays pass, ie: 60 => a minute's delay takes a second, default 1
+	SeedFile          string             // Which seed file to read in
+	EchoSeed          int                // 0/1 Echo the seed data to terminal
+	CurrentPath       string             // current
+	OSName            string             // OS name
+	Vatrate           float64            // Amount, for stores without tax rules of their own
+	Currency          string             // ISO 4217 code of stores without a currency of their own, default ZAR
+	Invoice_format    string             // invoice numbers, ie: {store}-{terminal}-{date}-{seq}, empty => random UUIDs
+	Store             int                // if <> 0 then store at that position in array is selected.
+	KafkaEnabled      int                // if = 1 then post docs to kafka
+	MongoAtlasEnabled int                // if = 1 then post docs to MongoDB
+	Json_to_file      int                // do we spool the created baskets and payments to a file/s
+	Sinks             []string           // sinks to write to, ie ["kafka", "mongo", "file"], when empty the 3 flags above are used
+	Output_path       string             // if yes above then pipe json here. we will spool the baskets to one file and the payments to a second.
+	TimeOffset        string             // what offset do we run with, from GMT / Zulu time
+	Max_items_basket  int                // max items in a basket
+	Max_quantity      int                // max quantity of items in a basket per product
+	Distributions     TDistributions     // shape of the basket size, quantity and payment delay, uniform by default
+	Tenders           map[string]float64 // weights of the tender types, cash, card, eft, voucher, loyalty, stores can override, default all card
+	Split_tender      float64            // probability a basket is paid with more than one tender, 0..1
+	Max_tenders       int                // most tenders a split basket is paid with, default 2
+	Declined_payment  float64            // probability a card, eft, voucher or loyalty attempt is declined, 0..1
+	Failed_payment    float64            // probability an attempt fails, never reaching the bank, ie: a timeout, 0..1
+	Max_retries       int                // times a declined or failed tender is tried again before the customer gives up
+	Partial_payment   float64            // probability the last tender only pays part of what is due, 0..1
+	Missing_payment   float64            // probability no payment ever arrives for a basket, 0..1
+	Refund_rate       float64            // probability some of a paid basket's items are brought back, 0..1
+	Max_refund_delay  string             // refunds happen up to this long after the sale, ie: 72h, default 24h
+	Customers         int                // loyalty customers generated on top of those in the seed file
+	Loyalty_share     float64            // share of the baskets sold to a loyalty customer, 0..1
+	Home_store_share  float64            // share of a customer's visits to their home store, 0..1, default 0.8
+	MetricsPort       int                // if > 0 then Prometheus metrics are served on http://<host>:<MetricsPort>/metrics
+	PushgatewayURL    string             // if set, ie: http://localhost:9091, metrics are pushed there every PushInterval seconds
+	PushInterval      int                // seconds between pushes to the Pushgateway, default 10
+	KafkaConfigFile   string             // Kafka configuration file
+	MongoConfigFile   string             // Mongo configuration file
+}
+
+// One stage of a load profile, the rate moves linearly from StartTps to EndTps over Duration
+type TLoadStage struct {
+	Name     string  // ie: ramp, hold, spike, rampdown, only used for logging
+	Duration string  // time.ParseDuration format, ie: 5m
+	StartTps float64 // events per second at the start of the stage
+	EndTps   float64 // events per second at the end of the stage, same as StartTps to hold a rate
+}
+
+// Which distribution each dimension is drawn from, within 1..Max_items_basket, 1..Max_quantity and 0..Max_payment_delay
+type TDistributions struct {
+	BasketSize   TDistribution // items (lines) per basket
+	Quantity     TDistribution // quantity per basket item
+	PaymentDelay TDistribution // seconds from sale to payment
+}
+
+// A distribution and its parameters, only those of the Type are used, values outside the bounds are drawn again
+type TDistribution struct {
+	Type    string    // uniform (default), poisson, normal, lognormal, zipf or empirical
+	Mean    float64   // poisson, normal
+	Stddev  float64   // normal
+	Mu      float64   // lognormal, mean of the log, e^Mu is the median
+	Sigma   float64   // lognormal, standard deviation of the log
+	S       float64   // zipf, > 1, the larger the steeper
+	V       float64   // zipf, >= 1
+	Values  []float64 // empirical, the values seen
+	Weights []float64 // empirical, how often each of Values is seen
+}
+
+// Multipliers on the basket arrival rate, empty lists are flat (all 1)
+type TTrafficShape struct {
+	Hours    []float64       // 24 multipliers, 00:00 to 23:00
+	Weekdays []float64       // 7 multipliers, Sunday to Saturday
+	Stores   []TStoreTraffic // per store curves, on top of the overall one
+}
+
+// A store's own curve, it is picked for baskets in proportion to it
+type TStoreTraffic struct {
+	Id       string    // store id as per the seed file
+	Hours    []float64 // 24 multipliers, 00:00 to 23:00
+	Weekdays []float64 // 7 multipliers, Sunday to Saturday
 }
 
 type TKafka struct {
@@ -30,6 +104,7 @@ type TKafka struct {
 	SchemaRegistryURL string
 	BasketTopicname   string
 	PaymentTopicname  string
+	RefundTopicname   string
 	Numpartitions     int
 	Replicationfactor int
 	Retension         string
@@ -39,6 +114,9 @@ type TKafka struct {
 	Sasl_username     string
 	Sasl_password     string
 	Flush_interval    int
+	Async             int    // 1 = hand messages to librdkafka and track delivery reports in the background instead of waiting on each one
+	Transactional     int    // 1 = exactly-once, baskets and payments are produced inside transactions committed every Flush_interval pairs
+	TransactionalId   string // transactional.id, defaults to <hostname>_<BasketTopicname>, must be unique per running producer
 }
 
 type TMongodb struct {
@@ -51,6 +129,7 @@ type TMongodb struct {
 	Datastore         string
 	Basketcollection  string
 	Paymentcollection string
+	Refundcollection  string
 	Batch_size        int
 }
 
@@ -85,13 +164,47 @@ type Tp_BasketItem struct {
 } */
 
 type TPClerkStruct struct {
-	Id   string `json:"id,omitempty"`
-	Name string `json:"name,omitempty"`
+	Id     string          `json:"id,omitempty"`
+	Name   string          `json:"name,omitempty"`
+	Store  string          `json:"store,omitempty"`  // home store id, the only store the clerk works at, empty => any store
+	Shifts []TOpeningHours `json:"shifts,omitempty"` // when the clerk works, in the store's timezone, empty => whenever it is open
 }
 
 type TStoreStruct struct {
-	Id   string `json:"id,omitempty"`
-	Name string `json:"name,omitempty"`
+	Id             string             `json:"id,omitempty"`
+	Name           string             `json:"name,omitempty"`
+	Timezone       string             `json:"timezone,omitempty"`       // IANA name, ie: Africa/Johannesburg, empty => local time
+	OpeningHours   []TOpeningHours    `json:"openingHours,omitempty"`   // empty => always open
+	ClosedDates    []string           `json:"closedDates,omitempty"`    // 2006-01-02, public holidays etc, in the store's timezone
+	Products       []string           `json:"products,omitempty"`       // ids of the products this store sells, empty => all of them
+	ProductWeights map[string]float64 `json:"productWeights,omitempty"` // popularity per product id at this store, overrides the product's weight
+	Prices         map[string]float64 `json:"prices,omitempty"`         // price per product id at this store, overrides the product's price
+	Tenders        map[string]float64 `json:"tenders,omitempty"`        // weights of the tender types at this store, overrides Tenders in *_app.json
+	Terminals      []string           `json:"terminals,omitempty"`      // the store's tills, ie: ["01", "02"], empty => 0 to 20
+	Country        string             `json:"country,omitempty"`        // ISO 3166 code, ie: ZA
+	Currency       string             `json:"currency,omitempty"`       // ISO 4217 code, ie: ZAR, empty => Currency in *_app.json
+	ExchangeRate   float64            `json:"exchangeRate,omitempty"`   // seed product prices x this = price in the store's currency, default 1
+	Tax            TTaxRules          `json:"tax,omitempty"`            // no rates => Vatrate in *_app.json on everything, prices exclusive
+}
+
+// A store's tax rules, a product is taxed at the first rate listing it or its category, else at the first rate listing neither
+type TTaxRules struct {
+	Inclusive bool       `json:"inclusive,omitempty"` // prices include tax
+	Rates     []TTaxRate `json:"rates,omitempty"`
+}
+
+type TTaxRate struct {
+	Code       string   `json:"code,omitempty"`       // ie: standard, reduced, zero
+	Rate       float64  `json:"rate,omitempty"`       // ie: 0.15, 0 for zero rated
+	Categories []string `json:"categories,omitempty"` // product categories taxed at this rate
+	Products   []string `json:"products,omitempty"`   // product ids taxed at this rate
+}
+
+// When a store trades, Close before Open means it trades past midnight
+type TOpeningHours struct {
+	Days  []string `json:"days,omitempty"`  // mon..sun, daily, weekdays, weekend
+	Open  string   `json:"open,omitempty"`  // HH:MM
+	Close string   `json:"close,omitempty"` // HH:MM
 }
 
 type TProductStruct struct {
@@ -100,10 +213,50 @@ type TProductStruct struct {
 	Brand    string  `json:"brand,omitempty"`
 	Category string  `json:"category,omitempty"`
 	Price    float64 `json:"price,omitempty"`
+	Weight   float64 `json:"weight,omitempty"` // popularity relative to the other products, default 1
+}
+
+// Market basket affinity, when a basket holds one of If (or anything in IfCategory) then with Probability
+// the Then products, and/or one product out of ThenCategory, are added to it
+type TAffinity struct {
+	Name         string   `json:"name,omitempty"`         // ie: pasta => sauce, only used for logging
+	If           []string `json:"if,omitempty"`           // anchor product ids
+	IfCategory   string   `json:"ifCategory,omitempty"`   // or an anchor category
+	Then         []string `json:"then,omitempty"`         // product ids added, all of them, a bundle
+	ThenCategory string   `json:"thenCategory,omitempty"` // a product picked by popularity from this category is added
+	Probability  float64  `json:"probability,omitempty"`  // 0..1
+}
+
+// A promotion, what it discounts, where and when, see internal/promo
+type TPromotion struct {
+	Name     string   `json:"name,omitempty"`     // shows on the discounted basket items
+	Type     string   `json:"type,omitempty"`     // percentage (percent off) or bxgy (buy x get y free)
+	Products []string `json:"products,omitempty"` // product ids it applies to
+	Category string   `json:"category,omitempty"` // or a whole category, neither => every product
+	Percent  float64  `json:"percent,omitempty"`  // percentage, ie: 10 => 10% off
+	Buy      int      `json:"buy,omitempty"`      // bxgy, buy this many
+	Get      int      `json:"get,omitempty"`      // bxgy, and get this many more free
+	Stores   []string `json:"stores,omitempty"`   // store ids running it, empty => all
+	From     string   `json:"from,omitempty"`     // first day of the campaign, 2006-01-02, empty => always been on
+	To       string   `json:"to,omitempty"`       // last day of the campaign, 2006-01-02, empty => never ends
+}
+
+// A loyalty customer, see internal/customer
+type TCustomer struct {
+	Id        string  `json:"id,omitempty"`
+	LoyaltyId string  `json:"loyaltyId,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Email     string  `json:"email,omitempty"`
+	HomeStore string  `json:"homeStore,omitempty"` // store id, default a random store
+	Visits    float64 `json:"visits,omitempty"`    // how often they shop relative to the others, default 1
+	Segment   string  `json:"segment,omitempty"`   // budget, regular (default) or premium, the bigger the spender the bigger the basket
 }
 
 type TPSeed struct {
-	Clerks   []TPClerkStruct  `json:"clerks,omitempty"`
-	Stores   []TStoreStruct   `json:"stores,omitempty"`
-	Products []TProductStruct `json:"products,omitempty"`
+	Clerks     []TPClerkStruct  `json:"clerks,omitempty"`
+	Stores     []TStoreStruct   `json:"stores,omitempty"`
+	Products   []TProductStruct `json:"products,omitempty"`
+	Affinities []TAffinity      `json:"affinities,omitempty"`
+	Promotions []TPromotion     `json:"promotions,omitempty"`
+	Customers  []TCustomer      `json:"customers,omitempty"`
 }
@@ -20,22 +20,94 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CardDetail struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scheme    string `protobuf:"bytes,1,opt,name=scheme,proto3" json:"scheme,omitempty"`       // visa, mastercard, amex
+	MaskedPan string `protobuf:"bytes,2,opt,name=maskedPan,proto3" json:"maskedPan,omitempty"` // 411111******1111
+}
+
+func (x *CardDetail) Reset() {
+	*x = CardDetail{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payment_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CardDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardDetail) ProtoMessage() {}
+
+func (x *CardDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardDetail.ProtoReflect.Descriptor instead.
+func (*CardDetail) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CardDetail) GetScheme() string {
+	if x != nil {
+		return x.Scheme
+	}
+	return ""
+}
+
+func (x *CardDetail) GetMaskedPan() string {
+	if x != nil {
+		return x.MaskedPan
+	}
+	return ""
+}
+
 type Pb_Payment struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	InvoiceNumber    string  `protobuf:"bytes,1,opt,name=invoiceNumber,proto3" json:"invoiceNumber,omitempty"`
-	PayDateTime      string  `protobuf:"bytes,2,opt,name=payDateTime,proto3" json:"payDateTime,omitempty"`
-	PayTimestamp     string  `protobuf:"bytes,3,opt,name=payTimestamp,proto3" json:"payTimestamp,omitempty"`
-	Paid             float64 `protobuf:"fixed64,4,opt,name=paid,proto3" json:"paid,omitempty"`
-	FinTransactionID string  `protobuf:"bytes,5,opt,name=finTransactionID,proto3" json:"finTransactionID,omitempty"`
+	InvoiceNumber    string      `protobuf:"bytes,1,opt,name=invoiceNumber,proto3" json:"invoiceNumber,omitempty"`
+	PayDateTime      string      `protobuf:"bytes,2,opt,name=payDateTime,proto3" json:"payDateTime,omitempty"`
+	PayTimestamp     string      `protobuf:"bytes,3,opt,name=payTimestamp,proto3" json:"payTimestamp,omitempty"`
+	Paid             float64     `protobuf:"fixed64,4,opt,name=paid,proto3" json:"paid,omitempty"`
+	FinTransactionID string      `protobuf:"bytes,5,opt,name=finTransactionID,proto3" json:"finTransactionID,omitempty"`
+	TenderType       string      `protobuf:"bytes,6,opt,name=tenderType,proto3" json:"tenderType,omitempty"`           // cash, card, eft, voucher, loyalty
+	TenderSeq        int32       `protobuf:"varint,7,opt,name=tenderSeq,proto3" json:"tenderSeq,omitempty"`            // 1.. the how manyth tender of the invoice this is
+	TenderCount      int32       `protobuf:"varint,8,opt,name=tenderCount,proto3" json:"tenderCount,omitempty"`        // how many tenders the invoice was paid with, their paid adds up to the basket total
+	Card             *CardDetail `protobuf:"bytes,9,opt,name=card,proto3" json:"card,omitempty"`                       // card only
+	Tendered         float64     `protobuf:"fixed64,10,opt,name=tendered,proto3" json:"tendered,omitempty"`            // cash only, what was handed over
+	Change           float64     `protobuf:"fixed64,11,opt,name=change,proto3" json:"change,omitempty"`                // cash only, change given
+	Reference        string      `protobuf:"bytes,12,opt,name=reference,proto3" json:"reference,omitempty"`            // eft reference / voucher number
+	PointsRedeemed   int64       `protobuf:"varint,13,opt,name=pointsRedeemed,proto3" json:"pointsRedeemed,omitempty"` // loyalty only, 1 point = 1 cent
+	Status           string      `protobuf:"bytes,14,opt,name=status,proto3" json:"status,omitempty"`                  // approved, declined, failed (never reached the bank) or partial
+	Attempt          int32       `protobuf:"varint,15,opt,name=attempt,proto3" json:"attempt,omitempty"`               // 1.. a declined or failed tender is tried again, up to Max_retries times
+	Reason           string      `protobuf:"bytes,16,opt,name=reason,proto3" json:"reason,omitempty"`                  // why the attempt was declined or failed
+	AmountDue        float64     `protobuf:"fixed64,17,opt,name=amountDue,proto3" json:"amountDue,omitempty"`          // what this tender was for, paid is what went through, 0 unless approved or partial
+	Currency         string      `protobuf:"bytes,18,opt,name=currency,proto3" json:"currency,omitempty"`              // ISO 4217, same as the basket's
+	PaidMinor        int64       `protobuf:"varint,19,opt,name=paidMinor,proto3" json:"paidMinor,omitempty"`           // the amounts in minor units (cents), exact, the paidMinor of a fully paid basket add up to its totalMinor
+	TenderedMinor    int64       `protobuf:"varint,20,opt,name=tenderedMinor,proto3" json:"tenderedMinor,omitempty"`
+	ChangeMinor      int64       `protobuf:"varint,21,opt,name=changeMinor,proto3" json:"changeMinor,omitempty"`
+	AmountDueMinor   int64       `protobuf:"varint,22,opt,name=amountDueMinor,proto3" json:"amountDueMinor,omitempty"`
 }
 
 func (x *Pb_Payment) Reset() {
 	*x = Pb_Payment{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_payment_proto_msgTypes[0]
+		mi := &file_payment_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -48,7 +120,7 @@ func (x *Pb_Payment) String() string {
 func (*Pb_Payment) ProtoMessage() {}
 
 func (x *Pb_Payment) ProtoReflect() protoreflect.Message {
-	mi := &file_payment_proto_msgTypes[0]
+	mi := &file_payment_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -61,7 +133,7 @@ func (x *Pb_Payment) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Pb_Payment.ProtoReflect.Descriptor instead.
 func (*Pb_Payment) Descriptor() ([]byte, []int) {
-	return file_payment_proto_rawDescGZIP(), []int{0}
+	return file_payment_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Pb_Payment) GetInvoiceNumber() string {
@@ -99,23 +171,180 @@ func (x *Pb_Payment) GetFinTransactionID() string {
 	return ""
 }
 
+func (x *Pb_Payment) GetTenderType() string {
+	if x != nil {
+		return x.TenderType
+	}
+	return ""
+}
+
+func (x *Pb_Payment) GetTenderSeq() int32 {
+	if x != nil {
+		return x.TenderSeq
+	}
+	return 0
+}
+
+func (x *Pb_Payment) GetTenderCount() int32 {
+	if x != nil {
+		return x.TenderCount
+	}
+	return 0
+}
+
+func (x *Pb_Payment) GetCard() *CardDetail {
+	if x != nil {
+		return x.Card
+	}
+	return nil
+}
+
+func (x *Pb_Payment) GetTendered() float64 {
+	if x != nil {
+		return x.Tendered
+	}
+	return 0
+}
+
+func (x *Pb_Payment) GetChange() float64 {
+	if x != nil {
+		return x.Change
+	}
+	return 0
+}
+
+func (x *Pb_Payment) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Pb_Payment) GetPointsRedeemed() int64 {
+	if x != nil {
+		return x.PointsRedeemed
+	}
+	return 0
+}
+
+func (x *Pb_Payment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Pb_Payment) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Pb_Payment) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Pb_Payment) GetAmountDue() float64 {
+	if x != nil {
+		return x.AmountDue
+	}
+	return 0
+}
+
+func (x *Pb_Payment) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Pb_Payment) GetPaidMinor() int64 {
+	if x != nil {
+		return x.PaidMinor
+	}
+	return 0
+}
+
+func (x *Pb_Payment) GetTenderedMinor() int64 {
+	if x != nil {
+		return x.TenderedMinor
+	}
+	return 0
+}
+
+func (x *Pb_Payment) GetChangeMinor() int64 {
+	if x != nil {
+		return x.ChangeMinor
+	}
+	return 0
+}
+
+func (x *Pb_Payment) GetAmountDueMinor() int64 {
+	if x != nil {
+		return x.AmountDueMinor
+	}
+	return 0
+}
+
 var File_payment_proto protoreflect.FileDescriptor
 
 var file_payment_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0xb8, 0x01, 0x0a, 0x0a, 0x50, 0x62, 0x5f, 0x50, 0x61,
-	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
-	0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e,
-	0x76, 0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x70,
-	0x61, 0x79, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x70, 0x61, 0x79, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x22, 0x0a,
-	0x0c, 0x70, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
-	0x04, 0x70, 0x61, 0x69, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x54, 0x72, 0x61, 0x6e,
-	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x10, 0x66, 0x69, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49,
-	0x44, 0x42, 0x03, 0x5a, 0x01, 0x2e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0x42, 0x0a, 0x0a, 0x43, 0x61, 0x72, 0x64, 0x44, 0x65,
+	0x74, 0x61, 0x69, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x6d, 0x61, 0x73, 0x6b, 0x65, 0x64, 0x50, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x6d, 0x61, 0x73, 0x6b, 0x65, 0x64, 0x50, 0x61, 0x6e, 0x22, 0xcb, 0x05, 0x0a, 0x0a, 0x50,
+	0x62, 0x5f, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x76,
+	0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12,
+	0x20, 0x0a, 0x0b, 0x70, 0x61, 0x79, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x04, 0x70, 0x61, 0x69, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x66, 0x69, 0x6e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x10, 0x66, 0x69, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x54,
+	0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x53,
+	0x65, 0x71, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x53, 0x65, 0x71, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x63, 0x61, 0x72, 0x64, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x43, 0x61, 0x72, 0x64,
+	0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x52, 0x04, 0x63, 0x61, 0x72, 0x64, 0x12, 0x1a, 0x0a, 0x08,
+	0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x26,
+	0x0a, 0x0e, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x64, 0x65, 0x65, 0x6d, 0x65, 0x64,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x64, 0x65, 0x65, 0x6d, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x75, 0x65, 0x18, 0x11, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x09, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x75, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61,
+	0x69, 0x64, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x13, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x70,
+	0x61, 0x69, 0x64, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x65, 0x64, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x14, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0d, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x64, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x12, 0x20,
+	0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x15, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x69, 0x6e, 0x6f, 0x72,
+	0x12, 0x26, 0x0a, 0x0e, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x75, 0x65, 0x4d, 0x69, 0x6e,
+	0x6f, 0x72, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x44, 0x75, 0x65, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x42, 0x03, 0x5a, 0x01, 0x2e, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -130,16 +359,18 @@ func file_payment_proto_rawDescGZIP() []byte {
 	return file_payment_proto_rawDescData
 }
 
-var file_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_payment_proto_goTypes = []interface{}{
-	(*Pb_Payment)(nil), // 0: types.Pb_Payment
+	(*CardDetail)(nil), // 0: types.CardDetail
+	(*Pb_Payment)(nil), // 1: types.Pb_Payment
 }
 var file_payment_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: types.Pb_Payment.card:type_name -> types.CardDetail
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_payment_proto_init() }
@@ -149,6 +380,18 @@ func file_payment_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_payment_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CardDetail); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payment_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Pb_Payment); i {
 			case 0:
 				return &v.state
@@ -167,7 +410,7 @@ func file_payment_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_payment_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,301 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.1
+// 	protoc        v5.26.1
+// source: refund.proto
+
+package types
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Pb_Refund struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RefundNumber    string        `protobuf:"bytes,1,opt,name=refundNumber,proto3" json:"refundNumber,omitempty"`
+	InvoiceNumber   string        `protobuf:"bytes,2,opt,name=invoiceNumber,proto3" json:"invoiceNumber,omitempty"` // the sale the goods were bought with
+	RefundDateTime  string        `protobuf:"bytes,3,opt,name=refundDateTime,proto3" json:"refundDateTime,omitempty"`
+	RefundTimestamp string        `protobuf:"bytes,4,opt,name=refundTimestamp,proto3" json:"refundTimestamp,omitempty"`
+	Store           *Idstruct     `protobuf:"bytes,5,opt,name=store,proto3" json:"store,omitempty"`             // where they were bought, and returned
+	Clerk           *Idstruct     `protobuf:"bytes,6,opt,name=clerk,proto3" json:"clerk,omitempty"`             // who took the return
+	BasketItems     []*BasketItem `protobuf:"bytes,7,rep,name=basketItems,proto3" json:"basketItems,omitempty"` // what came back, a subset of the sale's items, quantity at most what was bought
+	Nett            float64       `protobuf:"fixed64,8,opt,name=nett,proto3" json:"nett,omitempty"`
+	Vat             float64       `protobuf:"fixed64,9,opt,name=vat,proto3" json:"vat,omitempty"`
+	Total           float64       `protobuf:"fixed64,10,opt,name=total,proto3" json:"total,omitempty"`        // refunded to the customer
+	Reason          string        `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`        // damaged, wrong size, changed mind, ...
+	Currency        string        `protobuf:"bytes,12,opt,name=currency,proto3" json:"currency,omitempty"`    // ISO 4217, same as the sale's
+	Taxes           []*TaxLine    `protobuf:"bytes,13,rep,name=taxes,proto3" json:"taxes,omitempty"`          // vat refunded, by tax rate
+	NettMinor       int64         `protobuf:"varint,14,opt,name=nettMinor,proto3" json:"nettMinor,omitempty"` // the amounts in minor units (cents), exact, totalMinor == nettMinor + vatMinor
+	VatMinor        int64         `protobuf:"varint,15,opt,name=vatMinor,proto3" json:"vatMinor,omitempty"`
+	TotalMinor      int64         `protobuf:"varint,16,opt,name=totalMinor,proto3" json:"totalMinor,omitempty"`
+}
+
+func (x *Pb_Refund) Reset() {
+	*x = Pb_Refund{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_refund_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pb_Refund) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pb_Refund) ProtoMessage() {}
+
+func (x *Pb_Refund) ProtoReflect() protoreflect.Message {
+	mi := &file_refund_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pb_Refund.ProtoReflect.Descriptor instead.
+func (*Pb_Refund) Descriptor() ([]byte, []int) {
+	return file_refund_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Pb_Refund) GetRefundNumber() string {
+	if x != nil {
+		return x.RefundNumber
+	}
+	return ""
+}
+
+func (x *Pb_Refund) GetInvoiceNumber() string {
+	if x != nil {
+		return x.InvoiceNumber
+	}
+	return ""
+}
+
+func (x *Pb_Refund) GetRefundDateTime() string {
+	if x != nil {
+		return x.RefundDateTime
+	}
+	return ""
+}
+
+func (x *Pb_Refund) GetRefundTimestamp() string {
+	if x != nil {
+		return x.RefundTimestamp
+	}
+	return ""
+}
+
+func (x *Pb_Refund) GetStore() *Idstruct {
+	if x != nil {
+		return x.Store
+	}
+	return nil
+}
+
+func (x *Pb_Refund) GetClerk() *Idstruct {
+	if x != nil {
+		return x.Clerk
+	}
+	return nil
+}
+
+func (x *Pb_Refund) GetBasketItems() []*BasketItem {
+	if x != nil {
+		return x.BasketItems
+	}
+	return nil
+}
+
+func (x *Pb_Refund) GetNett() float64 {
+	if x != nil {
+		return x.Nett
+	}
+	return 0
+}
+
+func (x *Pb_Refund) GetVat() float64 {
+	if x != nil {
+		return x.Vat
+	}
+	return 0
+}
+
+func (x *Pb_Refund) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Pb_Refund) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Pb_Refund) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Pb_Refund) GetTaxes() []*TaxLine {
+	if x != nil {
+		return x.Taxes
+	}
+	return nil
+}
+
+func (x *Pb_Refund) GetNettMinor() int64 {
+	if x != nil {
+		return x.NettMinor
+	}
+	return 0
+}
+
+func (x *Pb_Refund) GetVatMinor() int64 {
+	if x != nil {
+		return x.VatMinor
+	}
+	return 0
+}
+
+func (x *Pb_Refund) GetTotalMinor() int64 {
+	if x != nil {
+		return x.TotalMinor
+	}
+	return 0
+}
+
+var File_refund_proto protoreflect.FileDescriptor
+
+var file_refund_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x1a, 0x0c, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x9a, 0x04, 0x0a, 0x09, 0x50, 0x62, 0x5f, 0x52, 0x65, 0x66, 0x75, 0x6e,
+	0x64, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x4e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
+	0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e,
+	0x76, 0x6f, 0x69, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x72,
+	0x65, 0x66, 0x75, 0x6e, 0x64, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x44, 0x61, 0x74, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65,
+	0x66, 0x75, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x25, 0x0a,
+	0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x64, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x05, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x12, 0x25, 0x0a, 0x05, 0x63, 0x6c, 0x65, 0x72, 0x6b, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x49, 0x64, 0x73, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x52, 0x05, 0x63, 0x6c, 0x65, 0x72, 0x6b, 0x12, 0x33, 0x0a, 0x0b, 0x62,
+	0x61, 0x73, 0x6b, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x42, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x49,
+	0x74, 0x65, 0x6d, 0x52, 0x0b, 0x62, 0x61, 0x73, 0x6b, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x65, 0x74, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04,
+	0x6e, 0x65, 0x74, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x03, 0x76, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x12, 0x24, 0x0a, 0x05, 0x74, 0x61, 0x78, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x78, 0x4c, 0x69, 0x6e, 0x65, 0x52,
+	0x05, 0x74, 0x61, 0x78, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x65, 0x74, 0x74, 0x4d, 0x69,
+	0x6e, 0x6f, 0x72, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x65, 0x74, 0x74, 0x4d,
+	0x69, 0x6e, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x76, 0x61, 0x74, 0x4d, 0x69, 0x6e, 0x6f, 0x72,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x76, 0x61, 0x74, 0x4d, 0x69, 0x6e, 0x6f, 0x72,
+	0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x69, 0x6e, 0x6f, 0x72,
+	0x42, 0x03, 0x5a, 0x01, 0x2e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_refund_proto_rawDescOnce sync.Once
+	file_refund_proto_rawDescData = file_refund_proto_rawDesc
+)
+
+func file_refund_proto_rawDescGZIP() []byte {
+	file_refund_proto_rawDescOnce.Do(func() {
+		file_refund_proto_rawDescData = protoimpl.X.CompressGZIP(file_refund_proto_rawDescData)
+	})
+	return file_refund_proto_rawDescData
+}
+
+var file_refund_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_refund_proto_goTypes = []interface{}{
+	(*Pb_Refund)(nil),  // 0: types.Pb_Refund
+	(*Idstruct)(nil),   // 1: types.Idstruct
+	(*BasketItem)(nil), // 2: types.BasketItem
+	(*TaxLine)(nil),    // 3: types.TaxLine
+}
+var file_refund_proto_depIdxs = []int32{
+	1, // 0: types.Pb_Refund.store:type_name -> types.Idstruct
+	1, // 1: types.Pb_Refund.clerk:type_name -> types.Idstruct
+	2, // 2: types.Pb_Refund.basketItems:type_name -> types.BasketItem
+	3, // 3: types.Pb_Refund.taxes:type_name -> types.TaxLine
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_refund_proto_init() }
+func file_refund_proto_init() {
+	if File_refund_proto != nil {
+		return
+	}
+	file_basket_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_refund_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pb_Refund); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_refund_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_refund_proto_goTypes,
+		DependencyIndexes: file_refund_proto_depIdxs,
+		MessageInfos:      file_refund_proto_msgTypes,
+	}.Build()
+	File_refund_proto = out.File
+	file_refund_proto_rawDesc = nil
+	file_refund_proto_goTypes = nil
+	file_refund_proto_depIdxs = nil
+}